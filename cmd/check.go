@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/detector"
+	"github.com/spf13/cobra"
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check that DDEV and Composer agree on the PHP version",
+	Long: `Compares DDEV's php_version with require.php and config.platform.php in
+composer.json and the platform section of composer.lock, all read below
+composer_root. Exits non-zero if they disagree, so it can run in CI.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runCheck,
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	projectPath, err := currentProjectPath()
+	if err != nil {
+		return err
+	}
+
+	project, err := loadProject(projectPath)
+	if err != nil {
+		return err
+	}
+
+	versions, err := detector.DetectPHPVersions(project)
+	if err != nil {
+		return err
+	}
+	for _, row := range [][2]string{
+		{"php_version", versions.DDEV},
+		{"require.php", versions.Require},
+		{"config.platform.php", versions.Platform},
+		{"composer.lock platform", versions.LockPlatform},
+	} {
+		value := row[1]
+		if value == "" {
+			value = "-"
+		}
+		fmt.Printf("%-24s %s\n", row[0], value)
+	}
+
+	mismatches := detector.CheckPHPVersions(versions)
+	if len(mismatches) == 0 {
+		fmt.Println("\nPHP versions are consistent")
+		return nil
+	}
+
+	fmt.Println()
+	for _, m := range mismatches {
+		fmt.Printf("! %s\n", m)
+	}
+	return fmt.Errorf("%d PHP version mismatch(es)", len(mismatches))
+}
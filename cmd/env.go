@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/dotenv"
+	"github.com/dkd-dobberkau/ddev-explain/internal/model"
+	"github.com/spf13/cobra"
+)
+
+const maskedValue = "********"
+
+var envCmd = &cobra.Command{
+	Use:   "env [service]",
+	Short: "Show the effective environment of each container",
+	Long: `Lists the variables DDEV passes to each container from .ddev/.env,
+.ddev/.env.<service> and web_environment, with the file that set them.
+Values of variables that look like secrets are masked unless --show-secrets is given.`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runEnv,
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+}
+
+func runEnv(cmd *cobra.Command, args []string) error {
+	projectPath, err := currentProjectPath()
+	if err != nil {
+		return err
+	}
+
+	project, err := loadProject(projectPath)
+	if err != nil {
+		return err
+	}
+	if !showSecretsFlag {
+		maskSecrets(project)
+	}
+
+	services := make([]string, 0, len(project.Environment))
+	for service := range project.Environment {
+		if len(args) == 0 || args[0] == service {
+			services = append(services, service)
+		}
+	}
+	if len(services) == 0 {
+		if len(args) > 0 {
+			return fmt.Errorf("no environment variables set for service %q", args[0])
+		}
+		fmt.Println("No environment variables configured")
+		return nil
+	}
+	sort.Strings(services)
+
+	for i, service := range services {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(service)
+
+		vars := project.Environment[service]
+		width := 0
+		for _, v := range vars {
+			if n := len(v.Key) + len(v.Value) + 1; n > width {
+				width = n
+			}
+		}
+		for _, v := range vars {
+			fmt.Printf("  %-*s  %s\n", width, v.Key+"="+v.Value, v.Source)
+		}
+	}
+
+	return nil
+}
+
+// maskSecrets hides values of variables whose names look sensitive, in the
+// per-service environment, the raw web_environment list, its provenance,
+// provider files and Dockerfile ARG/ENV instructions
+func maskSecrets(project *model.Project) {
+	for service, vars := range project.Environment {
+		project.Environment[service] = maskEnvVars(vars)
+	}
+
+	webEnvironment := make([]string, len(project.WebEnvironment))
+	for i, kv := range project.WebEnvironment {
+		webEnvironment[i] = maskAssignment(kv)
+	}
+	project.WebEnvironment = webEnvironment
+
+	// Provenance renders web_environment lists as "A=1, B=2"
+	for key, chain := range project.Provenance {
+		if key != "web_environment" && !strings.HasPrefix(key, "web_environment.") {
+			continue
+		}
+		masked := make([]model.Origin, len(chain))
+		for i, o := range chain {
+			o.Value = maskAssignmentList(o.Value)
+			masked[i] = o
+		}
+		project.Provenance[key] = masked
+	}
+
+	for i := range project.Providers {
+		project.Providers[i].Variables = maskEnvVars(project.Providers[i].Variables)
+	}
+
+	// Dockerfile ARG defaults and ENV values end up in the image
+	for i := range project.ImageCustomizations {
+		c := &project.ImageCustomizations[i]
+		c.Args = maskEnvVars(c.Args)
+		c.Env = maskEnvVars(c.Env)
+	}
+}
+
+// maskEnvVars returns a copy of vars with the values of secret-looking
+// variables masked
+func maskEnvVars(vars []model.EnvVar) []model.EnvVar {
+	if vars == nil {
+		return nil
+	}
+	masked := make([]model.EnvVar, len(vars))
+	for i, v := range vars {
+		if dotenv.LooksSecret(v.Key) && v.Value != "" {
+			v.Value = maskedValue
+		}
+		masked[i] = v
+	}
+	return masked
+}
+
+// maskAssignment masks the value of a KEY=value item with a secret name
+func maskAssignment(kv string) string {
+	if key, value, ok := strings.Cut(kv, "="); ok && value != "" && dotenv.LooksSecret(key) {
+		return key + "=" + maskedValue
+	}
+	return kv
+}
+
+var assignmentStart = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
+
+// maskAssignmentList masks the secret items of a ", "-joined list of
+// KEY=value items. A part that does not start with KEY= belongs to the
+// value of the item before it.
+func maskAssignmentList(list string) string {
+	var items []string
+	for _, part := range strings.Split(list, ", ") {
+		if len(items) > 0 && !assignmentStart.MatchString(part) {
+			items[len(items)-1] += ", " + part
+			continue
+		}
+		items = append(items, part)
+	}
+	for i, item := range items {
+		items[i] = maskAssignment(item)
+	}
+	return strings.Join(items, ", ")
+}
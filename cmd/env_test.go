@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/output"
+)
+
+func writeProject(t *testing.T, config string) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	ddevDir := filepath.Join(tmpDir, ".ddev")
+	if err := os.MkdirAll(ddevDir, 0755); err != nil {
+		t.Fatalf("failed to create .ddev: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(ddevDir, "config.yaml"), []byte(config), 0644); err != nil {
+		t.Fatalf("failed to write config.yaml: %v", err)
+	}
+	ddevHomeFlag = t.TempDir()
+	t.Cleanup(func() { ddevHomeFlag = "" })
+	return tmpDir
+}
+
+func TestMaskSecrets_Provenance(t *testing.T) {
+	projectPath := writeProject(t, `name: demo
+web_environment:
+  - DB_PASSWORD=hunter2, still secret
+  - APP_ENV=dev
+`)
+
+	project, err := loadProject(projectPath)
+	if err != nil {
+		t.Fatalf("loadProject failed: %v", err)
+	}
+	maskSecrets(project)
+
+	out, err := output.NewJSONFormatter(true).Format(project)
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if strings.Contains(out, "hunter2") || strings.Contains(out, "still secret") {
+		t.Errorf("expected secret to be masked in provenance, got %s", out)
+	}
+	if !strings.Contains(out, "DB_PASSWORD="+maskedValue) || !strings.Contains(out, "APP_ENV=dev") {
+		t.Errorf("expected masked and plain variables in provenance, got %s", out)
+	}
+}
+
+func TestRunWhy_MasksSecrets(t *testing.T) {
+	projectPath := writeProject(t, `name: demo
+web_environment: [DB_PASSWORD=hunter2, APP_ENV=dev]
+`)
+	t.Chdir(projectPath)
+
+	out := captureStdout(t, func() {
+		if err := runWhy(whyCmd, []string{"web_environment"}); err != nil {
+			t.Fatalf("runWhy failed: %v", err)
+		}
+	})
+	if strings.Contains(out, "hunter2") {
+		t.Errorf("expected why to mask secrets, got %s", out)
+	}
+	if !strings.Contains(out, "APP_ENV=dev") {
+		t.Errorf("expected plain variables to be shown, got %s", out)
+	}
+}
+
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	return string(out)
+}
+
+func TestMaskSecrets_DockerfileArgsAndEnv(t *testing.T) {
+	projectPath := writeProject(t, "name: demo\n")
+	dockerfile := `ARG GITHUB_TOKEN=ghp_secretvalue
+ARG PHP_MEMORY=512M
+ENV API_TOKEN=envsecretvalue
+`
+	buildDir := filepath.Join(projectPath, ".ddev", "web-build")
+	if err := os.MkdirAll(buildDir, 0755); err != nil {
+		t.Fatalf("failed to create web-build: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(buildDir, "Dockerfile"), []byte(dockerfile), 0644); err != nil {
+		t.Fatalf("failed to write Dockerfile: %v", err)
+	}
+
+	project, err := loadProject(projectPath)
+	if err != nil {
+		t.Fatalf("loadProject failed: %v", err)
+	}
+	maskSecrets(project)
+
+	for _, formatter := range []output.Formatter{
+		output.NewTextFormatter(true, false),
+		output.NewMarkdownFormatter(true, false),
+		output.NewJSONFormatter(false),
+	} {
+		out, err := formatter.Format(project)
+		if err != nil {
+			t.Fatalf("Format failed: %v", err)
+		}
+		if strings.Contains(out, "ghp_secretvalue") || strings.Contains(out, "envsecretvalue") {
+			t.Errorf("expected ARG and ENV secrets to be masked, got %s", out)
+		}
+		if !strings.Contains(out, maskedValue) || !strings.Contains(out, "512M") {
+			t.Errorf("expected masked and plain Dockerfile values, got %s", out)
+		}
+	}
+}
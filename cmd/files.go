@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/ddev"
+	"github.com/spf13/cobra"
+)
+
+var filesStatusFlag string
+
+var filesCmd = &cobra.Command{
+	Use:   "files",
+	Short: "Show which files in .ddev are generated and which are user-authored",
+	Long: `Lists every file in .ddev as generated (it carries #ddev-generated and
+DDEV may overwrite it), user-modified (no marker, so DDEV leaves it alone and
+it belongs to the project) or unknown (binary or unreadable).`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runFiles,
+}
+
+func init() {
+	filesCmd.Flags().StringVar(&filesStatusFlag, "status", "", "Only list files with this status: generated, user-modified, unknown")
+	rootCmd.AddCommand(filesCmd)
+}
+
+func runFiles(cmd *cobra.Command, args []string) error {
+	switch filesStatusFlag {
+	case "", ddev.FileGenerated, ddev.FileUserModified, ddev.FileUnknown:
+	default:
+		return fmt.Errorf("unknown status %q (expected generated, user-modified or unknown)", filesStatusFlag)
+	}
+
+	projectPath, err := currentProjectPath()
+	if err != nil {
+		return err
+	}
+
+	files, err := ddev.ClassifyFiles(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to scan .ddev: %w", err)
+	}
+
+	counts := make(map[string]int)
+	for _, f := range files {
+		counts[f.Status]++
+		if filesStatusFlag == "" || filesStatusFlag == f.Status {
+			fmt.Printf("%-14s %s\n", f.Status, f.Path)
+		}
+	}
+
+	fmt.Printf("\n%d generated, %d user-modified, %d unknown\n",
+		counts[ddev.FileGenerated], counts[ddev.FileUserModified], counts[ddev.FileUnknown])
+	return nil
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/ddev"
+	"github.com/dkd-dobberkau/ddev-explain/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var lifecycleFormatFlag string
+
+var lifecycleCmd = &cobra.Command{
+	Use:   "lifecycle [action]",
+	Short: "Show the hooks a ddev command runs, in order",
+	Long: `Prints the pre- and post- hook sequence of a ddev action such as start,
+import-db, snapshot or composer, with the container that runs each step and
+the config file that declared it. Hooks for omitted containers are marked
+as skipped. Without an action, every action that has hooks is shown.`,
+	Example: `  ddev-explain lifecycle start
+  ddev-explain lifecycle import-db --format markdown`,
+	Args:         cobra.MaximumNArgs(1),
+	ValidArgs:    ddev.HookActions(),
+	SilenceUsage: true,
+	RunE:         runLifecycle,
+}
+
+func init() {
+	lifecycleCmd.Flags().StringVarP(&lifecycleFormatFlag, "format", "f", "text", "Output format: text, markdown")
+	rootCmd.AddCommand(lifecycleCmd)
+}
+
+func runLifecycle(cmd *cobra.Command, args []string) error {
+	if lifecycleFormatFlag != "text" && lifecycleFormatFlag != "markdown" {
+		return fmt.Errorf("unknown format: %s", lifecycleFormatFlag)
+	}
+
+	actions := ddev.HookActions()
+	if len(args) > 0 {
+		if !containsString(actions, args[0]) {
+			return fmt.Errorf("unknown action %q (expected one of %s)", args[0], strings.Join(actions, ", "))
+		}
+		actions = args
+	}
+
+	projectPath, err := currentProjectPath()
+	if err != nil {
+		return err
+	}
+	project, err := loadProject(projectPath)
+	if err != nil {
+		return err
+	}
+
+	printed := 0
+	for _, action := range actions {
+		steps, hooks := ddev.Lifecycle(project, action)
+		// Listing every action, skip the ones without hooks
+		if len(args) == 0 && hooks == 0 {
+			continue
+		}
+		if printed > 0 {
+			fmt.Println()
+		}
+		if lifecycleFormatFlag == "markdown" {
+			fmt.Print(lifecycleMarkdown(action, steps))
+		} else {
+			fmt.Print(lifecycleText(action, steps))
+		}
+		printed++
+	}
+
+	if printed == 0 {
+		fmt.Println("No hooks configured")
+	}
+	return nil
+}
+
+func lifecycleText(action string, steps []ddev.LifecycleStep) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("ddev %s\n", action))
+
+	width, containerWidth, commandWidth := 0, 0, 0
+	for _, step := range steps {
+		if step.Hook == nil {
+			continue
+		}
+		width = max(width, len(step.Event))
+		containerWidth = max(containerWidth, len(step.Hook.Container))
+		commandWidth = max(commandWidth, len(hookCommand(*step.Hook)))
+	}
+
+	for i, step := range steps {
+		if step.Hook == nil {
+			sb.WriteString(fmt.Sprintf("  %2d. %-*s  [ddev %s runs]\n", i+1, width, "", action))
+			continue
+		}
+		h := step.Hook
+		line := fmt.Sprintf("  %2d. %-*s  %-*s  %-*s  %s", i+1, width, step.Event,
+			containerWidth, h.Container, commandWidth, hookCommand(*h), h.Source)
+		if step.Skipped != "" {
+			line += fmt.Sprintf("  (skipped: %s)", step.Skipped)
+		}
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
+
+func lifecycleMarkdown(action string, steps []ddev.LifecycleStep) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("### `ddev %s`\n\n", action))
+	sb.WriteString("| # | Hook | Runs in | Command | Declared in |\n")
+	sb.WriteString("|---|------|---------|---------|-------------|\n")
+
+	for i, step := range steps {
+		if step.Hook == nil {
+			sb.WriteString(fmt.Sprintf("| %d | | | _ddev %s runs_ | |\n", i+1, action))
+			continue
+		}
+		h := step.Hook
+		command := strings.ReplaceAll(hookCommand(*h), "|", `\|`)
+		container := h.Container
+		if step.Skipped != "" {
+			container += " _(skipped: " + step.Skipped + ")_"
+		}
+		sb.WriteString(fmt.Sprintf("| %d | %s | %s | `%s` | %s |\n", i+1, step.Event, container, command, h.Source))
+	}
+	return sb.String()
+}
+
+// hookCommand returns the command line a hook entry runs
+func hookCommand(h model.Hook) string {
+	if h.Kind == "composer" {
+		return "composer " + h.Command
+	}
+	return h.Command
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
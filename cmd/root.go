@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/dkd-dobberkau/ddev-explain/internal/ddev"
 	"github.com/dkd-dobberkau/ddev-explain/internal/detector"
@@ -13,11 +14,15 @@ import (
 )
 
 var (
-	formatFlag     string
-	allFlag        bool
-	devPathsFlag   bool
-	verboseFlag    bool
-	installCmdFlag bool
+	formatFlag      string
+	allFlag         bool
+	devPathsFlag    bool
+	verboseFlag     bool
+	installCmdFlag  bool
+	provenanceFlag  bool
+	ddevHomeFlag    string
+	ddevVersion     string
+	showSecretsFlag bool
 )
 
 var rootCmd = &cobra.Command{
@@ -39,6 +44,10 @@ func init() {
 	rootCmd.Flags().BoolVar(&devPathsFlag, "dev-paths", false, "Show only development paths")
 	rootCmd.Flags().BoolVarP(&verboseFlag, "verbose", "v", false, "Show additional details")
 	rootCmd.Flags().BoolVar(&installCmdFlag, "install-command", false, "Install as DDEV custom command")
+	rootCmd.PersistentFlags().StringVar(&ddevHomeFlag, "ddev-home", "", "DDEV global config directory (default $DDEV_HOME or ~/.ddev)")
+	rootCmd.PersistentFlags().StringVar(&ddevVersion, "ddev-version", "", "DDEV version whose defaults fill unset values (default from ddev_version_constraint, else latest)")
+	rootCmd.PersistentFlags().BoolVar(&showSecretsFlag, "show-secrets", false, "Show values of environment variables that look like secrets")
+	rootCmd.Flags().BoolVar(&provenanceFlag, "provenance", false, "Show which config file and line set each value")
 }
 
 func runExplain(cmd *cobra.Command, args []string) error {
@@ -49,35 +58,32 @@ func runExplain(cmd *cobra.Command, args []string) error {
 	var projectPaths []string
 
 	if allFlag {
-		paths, err := finder.FindAllProjects()
+		globalDir, err := globalDir()
+		if err != nil {
+			return err
+		}
+		paths, err := finder.FindAllProjectsIn(globalDir)
 		if err != nil {
 			return fmt.Errorf("failed to find projects: %w", err)
 		}
 		projectPaths = paths
 	} else {
-		cwd, err := os.Getwd()
+		projectPath, err := currentProjectPath()
 		if err != nil {
 			return err
 		}
-
-		projectPath, err := finder.FindProjectUpward(cwd)
-		if err != nil {
-			return fmt.Errorf("no DDEV project found in %s or parent directories", cwd)
-		}
 		projectPaths = []string{projectPath}
 	}
 
 	for _, projectPath := range projectPaths {
-		project, err := ddev.ParseConfig(projectPath)
+		project, err := loadProject(projectPath)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to parse %s: %v\n", projectPath, err)
 			continue
 		}
 
-		// Detect dev paths
-		devPaths, err := detector.DetectDevPaths(projectPath)
-		if err == nil {
-			project.DevPaths = devPaths
+		if !showSecretsFlag {
+			maskSecrets(project)
 		}
 
 		// If --dev-paths, only show development paths
@@ -93,11 +99,11 @@ func runExplain(cmd *cobra.Command, args []string) error {
 		var formatter output.Formatter
 		switch formatFlag {
 		case "json":
-			formatter = output.NewJSONFormatter()
+			formatter = output.NewJSONFormatter(provenanceFlag)
 		case "markdown":
-			formatter = output.NewMarkdownFormatter(verboseFlag)
+			formatter = output.NewMarkdownFormatter(verboseFlag, provenanceFlag)
 		default:
-			formatter = output.NewTextFormatter(verboseFlag)
+			formatter = output.NewTextFormatter(verboseFlag, provenanceFlag)
 		}
 
 		out, err := formatter.Format(project)
@@ -111,18 +117,70 @@ func runExplain(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// currentProjectPath finds the DDEV project containing the working directory
+func currentProjectPath() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	projectPath, err := finder.FindProjectUpward(cwd)
+	if err != nil {
+		return "", fmt.Errorf("no DDEV project found in %s or parent directories", cwd)
+	}
+	return projectPath, nil
+}
+
+// globalDir returns the DDEV global config directory from --ddev-home,
+// $DDEV_HOME or ~/.ddev
+func globalDir() (string, error) {
+	if ddevHomeFlag != "" {
+		return ddevHomeFlag, nil
+	}
+	return finder.GlobalDir()
+}
+
+// loadProject parses the DDEV config and runs all detectors for a project
+func loadProject(projectPath string) (*model.Project, error) {
+	globalDir, err := globalDir()
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := ddev.ParseConfigWithOptions(projectPath, ddev.Options{
+		GlobalDir:   globalDir,
+		DDEVVersion: ddevVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Detect dev paths
+	devPaths, err := detector.DetectProjectDevPaths(project)
+	if err == nil {
+		project.DevPaths = devPaths
+		project.Warnings = append(project.Warnings, detector.Warnings(devPaths)...)
+	}
+	// Unparsable composer files were already reported with the dev paths
+	if versions, err := detector.DetectPHPVersions(project); err == nil {
+		project.Warnings = append(project.Warnings, detector.CheckPHPVersions(versions)...)
+	}
+
+	return project, nil
+}
+
 func installDDEVCommand() error {
-	homeDir, err := os.UserHomeDir()
+	globalDir, err := globalDir()
 	if err != nil {
 		return err
 	}
 
-	cmdDir := fmt.Sprintf("%s/.ddev/commands/host", homeDir)
+	cmdDir := filepath.Join(globalDir, "commands", "host")
 	if err := os.MkdirAll(cmdDir, 0755); err != nil {
 		return err
 	}
 
-	cmdPath := fmt.Sprintf("%s/explain", cmdDir)
+	cmdPath := filepath.Join(cmdDir, "explain")
 	cmdContent := `#!/bin/bash
 ## Description: Summarize DDEV project configuration
 ## Usage: explain [flags]
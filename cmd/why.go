@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var whyCmd = &cobra.Command{
+	Use:   "why <key>",
+	Short: "Show which config files set a key and in what order",
+	Long: `Prints the override chain for a config key, e.g. php_version or database.version.
+Passing a section such as "database" or "hooks" shows every key below it.
+Secret web_environment values are masked unless --show-secrets is given.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runWhy,
+}
+
+func init() {
+	rootCmd.AddCommand(whyCmd)
+}
+
+func runWhy(cmd *cobra.Command, args []string) error {
+	projectPath, err := currentProjectPath()
+	if err != nil {
+		return err
+	}
+
+	project, err := loadProject(projectPath)
+	if err != nil {
+		return err
+	}
+	if !showSecretsFlag {
+		maskSecrets(project)
+	}
+
+	key := args[0]
+	var keys []string
+	for k := range project.Provenance {
+		if k == key || strings.HasPrefix(k, key+".") {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("no config file sets %q", key)
+	}
+	sort.Strings(keys)
+
+	for i, k := range keys {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Print(formatChain(k, project.Provenance[k]))
+	}
+
+	return nil
+}
+
+// formatChain renders the merge history of one key, ending with the
+// effective value
+func formatChain(key string, chain []model.Origin) string {
+	var sb strings.Builder
+
+	width := 0
+	for _, o := range chain {
+		if len(o.String()) > width {
+			width = len(o.String())
+		}
+	}
+
+	var effective []string
+	for i, o := range chain {
+		if o.Mode != "append" {
+			effective = nil
+		}
+		effective = append(effective, o.Value)
+		sb.WriteString(fmt.Sprintf("  %d. %-*s  %-7s %s\n", i+1, width, o.String(), o.Mode, o.Value))
+	}
+
+	header := fmt.Sprintf("%s = %s\n", key, strings.Join(effective, ", "))
+	return header + sb.String()
+}
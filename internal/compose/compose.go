@@ -0,0 +1,277 @@
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File represents the relevant parts of a .ddev/docker-compose.*.yaml file
+type File struct {
+	Path     string             `yaml:"-"`
+	Services map[string]Service `yaml:"services"`
+}
+
+// Service represents a service definition in a compose file
+type Service struct {
+	Image   string   `yaml:"image"`
+	Ports   []Port   `yaml:"ports"`
+	Volumes []Volume `yaml:"volumes"`
+}
+
+// Volume is a service volume in either short ("src:dst:ro") or long syntax
+type Volume struct {
+	Type        string `yaml:"type"` // "bind", "volume", "tmpfs"
+	Source      string `yaml:"source"`
+	Target      string `yaml:"target"`
+	ReadOnly    bool   `yaml:"read_only"`
+	Propagation string // bind propagation, e.g. "rshared"
+}
+
+// Port is a published port in either short ("8080:80/tcp") or long syntax
+type Port struct {
+	HostIP    string `yaml:"host_ip"`
+	Published string `yaml:"published"`
+	Target    string `yaml:"target"`
+	Protocol  string `yaml:"protocol"`
+}
+
+var propagationModes = map[string]bool{
+	"shared": true, "rshared": true,
+	"slave": true, "rslave": true,
+	"private": true, "rprivate": true,
+}
+
+// FindFiles returns the docker-compose.*.yaml and docker-compose.*.yml files
+// DDEV merges into a project, in lexical order
+func FindFiles(ddevDir string) ([]string, error) {
+	if _, err := os.Stat(ddevDir); err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, pattern := range []string{"docker-compose.*.yaml", "docker-compose.*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(ddevDir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// Load parses a single compose file, expanding ${VAR} references in all
+// values from env
+func Load(path string, env map[string]string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	if err := interpolateNode(&doc, env); err != nil {
+		return nil, fmt.Errorf("failed to interpolate %s: %w", filepath.Base(path), err)
+	}
+
+	file := &File{Path: path}
+	if err := doc.Decode(file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+
+	return file, nil
+}
+
+// interpolateNode expands variables in every scalar value, leaving mapping
+// keys untouched like docker compose does
+func interpolateNode(node *yaml.Node, env map[string]string) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if !strings.Contains(node.Value, "$") {
+			return nil
+		}
+		value, err := Interpolate(node.Value, env)
+		if err != nil {
+			return err
+		}
+		node.Value = value
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := interpolateNode(node.Content[i], env); err != nil {
+				return err
+			}
+		}
+	default:
+		for _, child := range node.Content {
+			if err := interpolateNode(child, env); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// LoadAll parses every compose file in ddevDir. Files that fail to parse
+// or interpolate are skipped; their errors are returned alongside the
+// files that loaded.
+func LoadAll(ddevDir string, env map[string]string) ([]*File, []error, error) {
+	paths, err := FindFiles(ddevDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var files []*File
+	var loadErrs []error
+	for _, path := range paths {
+		file, err := Load(path, env)
+		if err != nil {
+			loadErrs = append(loadErrs, err)
+			continue
+		}
+		files = append(files, file)
+	}
+
+	return files, loadErrs, nil
+}
+
+// LoadWarning describes the consequence of an error LoadAll returned
+func LoadWarning(err error) string {
+	return err.Error() + "; its services and volumes are ignored"
+}
+
+// ServiceNames returns the service names of a file in sorted order
+func (f *File) ServiceNames() []string {
+	names := make([]string, 0, len(f.Services))
+	for name := range f.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UnmarshalYAML accepts both the short string and the long mapping syntax
+func (v *Volume) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*v = parseShortVolume(node.Value)
+		return nil
+	}
+
+	var long struct {
+		Type     string `yaml:"type"`
+		Source   string `yaml:"source"`
+		Target   string `yaml:"target"`
+		ReadOnly bool   `yaml:"read_only"`
+		Bind     struct {
+			Propagation string `yaml:"propagation"`
+		} `yaml:"bind"`
+	}
+	if err := node.Decode(&long); err != nil {
+		return err
+	}
+
+	*v = Volume{
+		Type:        long.Type,
+		Source:      long.Source,
+		Target:      long.Target,
+		ReadOnly:    long.ReadOnly,
+		Propagation: long.Bind.Propagation,
+	}
+	return nil
+}
+
+// parseShortVolume parses "source:target[:options]" where options is a
+// comma separated list such as "ro,rshared"
+func parseShortVolume(spec string) Volume {
+	parts := strings.SplitN(spec, ":", 3)
+	if len(parts) == 1 {
+		return Volume{Type: "volume", Target: parts[0]}
+	}
+
+	v := Volume{Source: parts[0], Target: parts[1]}
+	if isHostPath(v.Source) {
+		v.Type = "bind"
+	} else {
+		v.Type = "volume"
+	}
+
+	if len(parts) == 3 {
+		for _, opt := range strings.Split(parts[2], ",") {
+			switch {
+			case opt == "ro":
+				v.ReadOnly = true
+			case propagationModes[opt]:
+				v.Propagation = opt
+			}
+		}
+	}
+
+	return v
+}
+
+// isHostPath reports whether a short-syntax volume source is a host path
+// rather than a named volume
+func isHostPath(source string) bool {
+	return strings.HasPrefix(source, ".") || strings.HasPrefix(source, "/") ||
+		strings.HasPrefix(source, "~")
+}
+
+// UnmarshalYAML accepts both the short string and the long mapping syntax
+func (p *Port) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*p = parseShortPort(node.Value)
+		return nil
+	}
+
+	type rawPort Port
+	var long rawPort
+	if err := node.Decode(&long); err != nil {
+		return err
+	}
+	*p = Port(long)
+	return nil
+}
+
+// parseShortPort parses "[host_ip:][published:]target[/protocol]"
+func parseShortPort(spec string) Port {
+	var p Port
+	if idx := strings.LastIndex(spec, "/"); idx >= 0 {
+		p.Protocol = spec[idx+1:]
+		spec = spec[:idx]
+	}
+
+	parts := strings.Split(spec, ":")
+	switch len(parts) {
+	case 1:
+		p.Target = parts[0]
+	case 2:
+		p.Published, p.Target = parts[0], parts[1]
+	default:
+		p.HostIP = strings.Join(parts[:len(parts)-2], ":")
+		p.Published, p.Target = parts[len(parts)-2], parts[len(parts)-1]
+	}
+
+	return p
+}
+
+// String formats the port in short syntax
+func (p Port) String() string {
+	s := p.Target
+	if p.Published != "" {
+		s = p.Published + ":" + s
+	}
+	if p.HostIP != "" {
+		s = p.HostIP + ":" + s
+	}
+	if p.Protocol != "" && p.Protocol != "tcp" {
+		s += "/" + p.Protocol
+	}
+	return s
+}
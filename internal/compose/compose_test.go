@@ -0,0 +1,172 @@
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadAll_BothExtensionsAndSyntaxes(t *testing.T) {
+	ddevDir := t.TempDir()
+
+	shortSyntax := `services:
+  redis:
+    image: redis:7
+    ports:
+      - "6379:6379"
+    volumes:
+      - ../shared:/var/www/shared:ro,rshared
+      - redis-data:/data
+`
+	longSyntax := `services:
+  web:
+    volumes:
+      - type: bind
+        source: ../../libs
+        target: /var/www/libs
+        read_only: true
+        bind:
+          propagation: rslave
+      - type: volume
+        source: cache
+        target: /cache
+    ports:
+      - target: 5173
+        published: "5173"
+        protocol: udp
+`
+	files := map[string]string{
+		"docker-compose.redis.yaml": shortSyntax,
+		"docker-compose.libs.yml":   longSyntax,
+		"docker-compose.yaml":       "services: {}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(ddevDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	loaded, loadErrs, err := LoadAll(ddevDir, nil)
+	if err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	if len(loadErrs) != 0 {
+		t.Errorf("expected all files to load, got %v", loadErrs)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("expected 2 compose files, got %d", len(loaded))
+	}
+
+	// Lexical order: docker-compose.libs.yml before docker-compose.redis.yaml
+	libs := loaded[0].Services["web"]
+	if len(libs.Volumes) != 2 {
+		t.Fatalf("expected 2 long-syntax volumes, got %d", len(libs.Volumes))
+	}
+	bind := libs.Volumes[0]
+	if bind.Type != "bind" || bind.Source != "../../libs" || !bind.ReadOnly || bind.Propagation != "rslave" {
+		t.Errorf("unexpected long-syntax bind volume: %+v", bind)
+	}
+	if libs.Volumes[1].Type != "volume" {
+		t.Errorf("expected named volume, got %+v", libs.Volumes[1])
+	}
+	if len(libs.Ports) != 1 || libs.Ports[0].String() != "5173:5173/udp" {
+		t.Errorf("unexpected long-syntax port: %+v", libs.Ports)
+	}
+
+	redis := loaded[1].Services["redis"]
+	short := redis.Volumes[0]
+	if short.Type != "bind" || short.Target != "/var/www/shared" || !short.ReadOnly || short.Propagation != "rshared" {
+		t.Errorf("unexpected short-syntax bind volume: %+v", short)
+	}
+	if redis.Volumes[1].Type != "volume" {
+		t.Errorf("expected named volume, got %+v", redis.Volumes[1])
+	}
+	if len(redis.Ports) != 1 || redis.Ports[0].String() != "6379:6379" {
+		t.Errorf("unexpected short-syntax port: %+v", redis.Ports)
+	}
+}
+
+func TestParseShortPort(t *testing.T) {
+	tests := map[string]Port{
+		"80":                {Target: "80"},
+		"8080:80":           {Published: "8080", Target: "80"},
+		"127.0.0.1:8080:80": {HostIP: "127.0.0.1", Published: "8080", Target: "80"},
+		"8080:80/udp":       {Published: "8080", Target: "80", Protocol: "udp"},
+	}
+	for spec, want := range tests {
+		if got := parseShortPort(spec); got != want {
+			t.Errorf("parseShortPort(%q) = %+v, want %+v", spec, got, want)
+		}
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	env := map[string]string{
+		"DDEV_APPROOT":  "/srv/project",
+		"DDEV_SITENAME": "shop",
+		"EMPTY":         "",
+	}
+
+	tests := map[string]string{
+		"${DDEV_APPROOT}/../shared":       "/srv/project/../shared",
+		"$DDEV_SITENAME-redis":            "shop-redis",
+		"${MISSING:-fallback}":            "fallback",
+		"${EMPTY:-fallback}":              "fallback",
+		"${EMPTY-fallback}":               "",
+		"${MISSING-${DDEV_SITENAME}}":     "shop",
+		"${DDEV_SITENAME:+set}":           "set",
+		"${MISSING:+set}":                 "",
+		"$$HOME":                          "$HOME",
+		"${DDEV_SITENAME:?name required}": "shop",
+		"image:${MISSING}":                "image:",
+	}
+	for in, want := range tests {
+		got, err := Interpolate(in, env)
+		if err != nil {
+			t.Errorf("Interpolate(%q) failed: %v", in, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("Interpolate(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	if _, err := Interpolate("${EMPTY:?must be set}", env); err == nil {
+		t.Error("expected error for ${EMPTY:?...}")
+	}
+	if _, err := Interpolate("${MISSING?must be set}", env); err == nil {
+		t.Error("expected error for ${MISSING?...}")
+	}
+	if _, err := Interpolate("${EMPTY?must be set}", env); err != nil {
+		t.Errorf("expected ${EMPTY?...} to accept an empty value, got %v", err)
+	}
+}
+
+func TestLoad_Interpolation(t *testing.T) {
+	ddevDir := t.TempDir()
+	composeYAML := `services:
+  solr:
+    image: solr:${SOLR_VERSION:-9}
+    volumes:
+      - ${DDEV_APPROOT}/../shared:/var/www/shared
+    labels:
+      com.ddev.site-name: ${DDEV_SITENAME}
+`
+	path := filepath.Join(ddevDir, "docker-compose.solr.yaml")
+	if err := os.WriteFile(path, []byte(composeYAML), 0644); err != nil {
+		t.Fatalf("failed to write compose file: %v", err)
+	}
+
+	file, err := Load(path, map[string]string{"DDEV_APPROOT": "/srv/project", "DDEV_SITENAME": "shop"})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	solr := file.Services["solr"]
+	if solr.Image != "solr:9" {
+		t.Errorf("expected image 'solr:9', got '%s'", solr.Image)
+	}
+	if len(solr.Volumes) != 1 || solr.Volumes[0].Type != "bind" || solr.Volumes[0].Source != "/srv/project/../shared" {
+		t.Errorf("expected interpolated bind volume, got %+v", solr.Volumes)
+	}
+}
@@ -0,0 +1,75 @@
+package compose
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/dotenv"
+	"github.com/dkd-dobberkau/ddev-explain/internal/model"
+)
+
+// ProjectEnv returns the variables available for interpolation in a
+// project's compose files. Like docker compose, the shell environment wins
+// over .ddev/.env, and the DDEV_* variables DDEV exports win over both.
+func ProjectEnv(project *model.Project) map[string]string {
+	env := make(map[string]string)
+
+	if entries, err := dotenv.ParseFile(filepath.Join(project.Path, ".ddev", ".env")); err == nil {
+		for k, v := range dotenv.ToMap(entries) {
+			env[k] = v
+		}
+	}
+
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+	if _, ok := env["HOME"]; !ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			env["HOME"] = home
+		}
+	}
+
+	for k, v := range ddevVariables(project) {
+		env[k] = v
+	}
+
+	return env
+}
+
+// ddevVariables mirrors the DDEV_* variables DDEV sets when it runs
+// docker compose for a project
+func ddevVariables(project *model.Project) map[string]string {
+	vars := map[string]string{
+		"DDEV_APPROOT":        project.Path,
+		"DDEV_SITENAME":       project.Name,
+		"DDEV_PROJECT":        project.Name,
+		"DDEV_PROJECT_TYPE":   project.Type,
+		"DDEV_DOCROOT":        project.Docroot,
+		"DDEV_PHP_VERSION":    project.PHPVersion,
+		"DDEV_WEBSERVER_TYPE": project.Webserver,
+		"DDEV_COMPOSER_ROOT":  filepath.Join("/var/www/html", project.ComposerRoot),
+	}
+	if project.Database.Type != "" {
+		vars["DDEV_DATABASE"] = project.Database.Type + ":" + project.Database.Version
+	}
+
+	var hostnames []string
+	for _, u := range project.URLs {
+		if parsed, err := url.Parse(u); err == nil && parsed.Scheme == "https" {
+			hostnames = append(hostnames, parsed.Hostname())
+		}
+	}
+	if len(hostnames) > 0 {
+		vars["DDEV_HOSTNAME"] = strings.Join(hostnames, ",")
+		vars["DDEV_PRIMARY_URL"] = project.URLs[0]
+		if tld, ok := strings.CutPrefix(hostnames[0], project.Name+"."); ok {
+			vars["DDEV_TLD"] = tld
+		}
+	}
+
+	return vars
+}
@@ -0,0 +1,132 @@
+package compose
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Interpolate expands $VAR and ${VAR} references the way docker compose
+// does, including ${VAR:-default}, ${VAR-default}, ${VAR:?error},
+// ${VAR?error}, ${VAR:+alt} and ${VAR+alt}. "$$" is a literal "$".
+// Unset variables without a default expand to an empty string.
+func Interpolate(s string, env map[string]string) (string, error) {
+	var sb strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 >= len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+
+		next := s[i+1]
+		switch {
+		case next == '$':
+			sb.WriteByte('$')
+			i++
+		case next == '{':
+			end := matchingBrace(s, i+1)
+			if end < 0 {
+				return "", fmt.Errorf("unterminated variable reference in %q", s)
+			}
+			value, err := expandBraced(s[i+2:end], env)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(value)
+			i = end
+		case isNameStart(next):
+			j := i + 1
+			for j < len(s) && isNameChar(s[j]) {
+				j++
+			}
+			sb.WriteString(env[s[i+1:j]])
+			i = j - 1
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// expandBraced resolves the inside of ${...}
+func expandBraced(expr string, env map[string]string) (string, error) {
+	name := expr
+	for i := 0; i < len(expr); i++ {
+		if !isNameChar(expr[i]) {
+			name = expr[:i]
+			break
+		}
+	}
+	if name == "" {
+		return "", fmt.Errorf("invalid variable reference ${%s}", expr)
+	}
+
+	value, set := env[name]
+	op := expr[len(name):]
+	if op == "" {
+		return value, nil
+	}
+
+	// ":" variants also treat an empty value as unset
+	emptyIsUnset := strings.HasPrefix(op, ":")
+	op = strings.TrimPrefix(op, ":")
+	if len(op) == 0 {
+		return "", fmt.Errorf("invalid variable reference ${%s}", expr)
+	}
+	present := set && (!emptyIsUnset || value != "")
+	arg := op[1:]
+
+	switch op[0] {
+	case '-':
+		if present {
+			return value, nil
+		}
+		return Interpolate(arg, env)
+	case '+':
+		if present {
+			return Interpolate(arg, env)
+		}
+		return "", nil
+	case '?':
+		if present {
+			return value, nil
+		}
+		msg, err := Interpolate(arg, env)
+		if err != nil {
+			return "", err
+		}
+		if msg == "" {
+			msg = "required variable is missing"
+		}
+		return "", fmt.Errorf("%s: %s", name, msg)
+	}
+
+	return "", fmt.Errorf("invalid variable reference ${%s}", expr)
+}
+
+// matchingBrace returns the index of the "}" closing the "{" at open,
+// allowing nested ${...} in defaults
+func matchingBrace(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
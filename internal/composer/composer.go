@@ -1,30 +1,176 @@
 package composer
 
 import (
+	"archive/zip"
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 )
 
 type ComposerJSON struct {
-	Name         string       `json:"name"`
-	Type         string       `json:"type"`
-	Repositories []Repository `json:"repositories"`
+	Name         string            `json:"name"`
+	Type         string            `json:"type"`
+	Description  string            `json:"description"`
+	Version      string            `json:"version"`
+	Require      map[string]string `json:"require"`
+	RequireDev   map[string]string `json:"require-dev"`
+	Repositories Repositories      `json:"repositories"`
+	Extra        Extra             `json:"extra"`
+	Config       Config            `json:"config"`
+}
+
+// Config holds the parts of the config section ddev-explain uses
+type Config struct {
+	VendorDir string   `json:"vendor-dir"`
+	Platform  Platform `json:"platform"`
+}
+
+// Platform maps platform packages such as php or ext-intl to versions.
+// Composer writes an empty platform as [] and disables a package with false;
+// both decode to no entry.
+type Platform map[string]string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (p *Platform) UnmarshalJSON(data []byte) error {
+	var list []any
+	if json.Unmarshal(data, &list) == nil && len(list) == 0 {
+		*p = nil
+		return nil
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = make(Platform, len(raw))
+	for name, value := range raw {
+		if version, ok := value.(string); ok {
+			(*p)[name] = version
+		}
+	}
+	return nil
+}
+
+// Repositories is the repositories section. Composer accepts a list or an
+// object keyed by repository name; both decode in file order, and entries
+// that are not objects, such as "packagist.org": false, are skipped.
+type Repositories []Repository
+
+// UnmarshalJSON implements json.Unmarshaler
+func (r *Repositories) UnmarshalJSON(data []byte) error {
+	var entries []json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		// Object form: decode token by token to keep the file order
+		dec := json.NewDecoder(bytes.NewReader(data))
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+			return fmt.Errorf("repositories must be a list or an object")
+		}
+		entries = nil
+		for dec.More() {
+			if _, err := dec.Token(); err != nil {
+				return err
+			}
+			var entry json.RawMessage
+			if err := dec.Decode(&entry); err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	*r = nil
+	for _, entry := range entries {
+		if trimmed := bytes.TrimSpace(entry); len(trimmed) == 0 || trimmed[0] != '{' {
+			continue
+		}
+		var repo Repository
+		if err := json.Unmarshal(entry, &repo); err != nil {
+			return err
+		}
+		*r = append(*r, repo)
+	}
+	return nil
+}
+
+// Extra holds the parts of the extra section ddev-explain uses
+type Extra struct {
+	BranchAlias map[string]string `json:"branch-alias"`
 }
 
 type Repository struct {
-	Type string `json:"type"`
-	URL  string `json:"url"`
+	Type    string            `json:"type"`
+	URL     string            `json:"url"`
+	Options RepositoryOptions `json:"options"`
 }
 
-// ParsePathRepositories extracts path-type repositories from composer.json
-func ParsePathRepositories(projectPath string) ([]string, error) {
+// RepositoryOptions holds the options of a path repository
+type RepositoryOptions struct {
+	Symlink  *bool             `json:"symlink"`  // false copies the package instead of linking it
+	Relative *bool             `json:"relative"` // Keep the url relative in composer.lock
+	Versions map[string]string `json:"versions"` // Package name -> version to report
+}
+
+// Copied reports whether composer mirrors the package into vendor/ instead
+// of symlinking it, so edits only arrive with composer update
+func (r Repository) Copied() bool {
+	return r.Type == "path" && r.Options.Symlink != nil && !*r.Options.Symlink
+}
+
+// IsLocal reports whether the repository url points at the local
+// filesystem rather than a remote host
+func (r Repository) IsLocal() bool {
+	if r.URL == "" {
+		return false
+	}
+	if strings.HasPrefix(r.URL, "file://") {
+		return true
+	}
+	// https://, ssh://, git@host:org/repo
+	return !strings.Contains(r.URL, "://") && !strings.Contains(r.URL, "@")
+}
+
+// LocalPath returns the filesystem path of a local repository url
+func (r Repository) LocalPath() string {
+	return strings.TrimPrefix(r.URL, "file://")
+}
+
+// Root returns the directory holding composer.json: the project root, or
+// DDEV's composer_root below it
+func Root(projectPath, composerRoot string) string {
+	if composerRoot == "" {
+		return projectPath
+	}
+	if filepath.IsAbs(composerRoot) {
+		return composerRoot
+	}
+	return filepath.Join(projectPath, composerRoot)
+}
+
+// VendorDir returns the vendor directory of the composer project in root,
+// honoring config.vendor-dir
+func VendorDir(root string) string {
+	vendorDir := "vendor"
+	if c, err := ParseComposerJSON(root); err == nil && c.Config.VendorDir != "" {
+		vendorDir = c.Config.VendorDir
+	}
+	if filepath.IsAbs(vendorDir) {
+		return vendorDir
+	}
+	return filepath.Join(root, vendorDir)
+}
+
+// ParseRepositories returns the repositories declared in composer.json
+func ParseRepositories(projectPath string) ([]Repository, error) {
 	composerPath := filepath.Join(projectPath, "composer.json")
 
 	data, err := os.ReadFile(composerPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return []string{}, nil
+			return []Repository{}, nil
 		}
 		return nil, err
 	}
@@ -34,8 +180,18 @@ func ParsePathRepositories(projectPath string) ([]string, error) {
 		return nil, err
 	}
 
+	return composer.Repositories, nil
+}
+
+// ParsePathRepositories extracts path-type repositories from composer.json
+func ParsePathRepositories(projectPath string) ([]string, error) {
+	repos, err := ParseRepositories(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
 	var paths []string
-	for _, repo := range composer.Repositories {
+	for _, repo := range repos {
 		if repo.Type == "path" {
 			paths = append(paths, repo.URL)
 		}
@@ -44,19 +200,66 @@ func ParsePathRepositories(projectPath string) ([]string, error) {
 	return paths, nil
 }
 
-// GetPackageType returns the type field from a composer.json
-func GetPackageType(packagePath string) (string, error) {
-	composerPath := filepath.Join(packagePath, "composer.json")
-
-	data, err := os.ReadFile(composerPath)
+// ParseComposerJSON reads the composer.json in a directory
+func ParseComposerJSON(dir string) (*ComposerJSON, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "composer.json"))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	var composer ComposerJSON
 	if err := json.Unmarshal(data, &composer); err != nil {
-		return "", err
+		return nil, err
+	}
+	return &composer, nil
+}
+
+// Constraint returns the version constraint the package requires name
+// with, and whether it is a require-dev entry
+func (c *ComposerJSON) Constraint(name string) (string, bool) {
+	if constraint, ok := c.Require[name]; ok {
+		return constraint, false
+	}
+	if constraint, ok := c.RequireDev[name]; ok {
+		return constraint, true
 	}
+	return "", false
+}
 
+// GetPackageType returns the type field from a composer.json
+func GetPackageType(packagePath string) (string, error) {
+	composer, err := ParseComposerJSON(packagePath)
+	if err != nil {
+		return "", err
+	}
 	return composer.Type, nil
 }
+
+// ReadArtifact reads the composer.json inside a package zip of an artifact
+// repository, either at the root or in a single top-level directory
+func ReadArtifact(zipPath string) (*ComposerJSON, error) {
+	archive, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	for _, f := range archive.File {
+		if path.Base(f.Name) != "composer.json" || strings.Count(strings.Trim(f.Name, "/"), "/") > 1 {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+
+		var composer ComposerJSON
+		if err := json.NewDecoder(r).Decode(&composer); err != nil {
+			return nil, err
+		}
+		return &composer, nil
+	}
+
+	return nil, fmt.Errorf("no composer.json in %s", filepath.Base(zipPath))
+}
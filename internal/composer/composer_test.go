@@ -1,6 +1,7 @@
 package composer
 
 import (
+	"archive/zip"
 	"os"
 	"path/filepath"
 	"testing"
@@ -53,3 +54,190 @@ func TestParsePathRepositories_NoFile(t *testing.T) {
 		t.Errorf("expected empty paths for missing composer.json, got %d", len(paths))
 	}
 }
+
+func TestParseLock(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	lockJSON := `{
+	"packages": [
+		{"name": "acme/local", "version": "dev-main", "dist": {"type": "path", "url": "packages/local"}},
+		{"name": "acme/remote", "version": "2.0.1", "source": {"type": "git", "url": "https://example.com/remote.git"}, "dist": {"type": "zip", "url": "https://example.com/remote.zip"}}
+	],
+	"packages-dev": [
+		{"name": "acme/tools", "version": "1.0.0", "source": {"type": "git", "url": "https://example.com/tools.git"}}
+	]
+}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "composer.lock"), []byte(lockJSON), 0644); err != nil {
+		t.Fatalf("failed to write composer.lock: %v", err)
+	}
+
+	lock, err := ParseLock(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseLock failed: %v", err)
+	}
+
+	expected := map[string]string{"acme/local": "path", "acme/remote": "zip", "acme/tools": "git"}
+	for name, from := range expected {
+		pkg, ok := lock.Find(name)
+		if !ok {
+			t.Errorf("expected to find %s", name)
+			continue
+		}
+		if pkg.InstalledFrom() != from {
+			t.Errorf("expected %s to be installed from %s, got %s", name, from, pkg.InstalledFrom())
+		}
+	}
+
+	if _, ok := lock.Find("acme/missing"); ok {
+		t.Error("expected acme/missing not to be found")
+	}
+}
+
+func TestParseLock_NoFile(t *testing.T) {
+	lock, err := ParseLock(t.TempDir())
+	if err != nil || lock != nil {
+		t.Errorf("expected nil lock without error, got %v, %v", lock, err)
+	}
+}
+
+func TestParseRepositories_Options(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	composerJSON := `{
+	"repositories": [
+		{"type": "path", "url": "packages/*", "options": {"symlink": false, "relative": true, "versions": {"acme/site": "2.0.0"}}},
+		{"type": "path", "url": "../linked"},
+		{"type": "vcs", "url": "../checkouts/lib"},
+		{"type": "vcs", "url": "git@github.com:acme/lib.git"},
+		{"type": "artifact", "url": "file:///opt/artifacts"}
+	]
+}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "composer.json"), []byte(composerJSON), 0644); err != nil {
+		t.Fatalf("failed to write composer.json: %v", err)
+	}
+
+	repos, err := ParseRepositories(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseRepositories failed: %v", err)
+	}
+	if len(repos) != 5 {
+		t.Fatalf("expected 5 repositories, got %d", len(repos))
+	}
+
+	if !repos[0].Copied() || repos[0].Options.Versions["acme/site"] != "2.0.0" {
+		t.Errorf("expected copied path repository with versions, got %+v", repos[0])
+	}
+	if repos[0].Options.Relative == nil || !*repos[0].Options.Relative {
+		t.Error("expected relative option to be set")
+	}
+	if repos[1].Copied() {
+		t.Error("expected path repository without options to be symlinked")
+	}
+	if !repos[2].IsLocal() || repos[3].IsLocal() {
+		t.Errorf("expected only the relative vcs url to be local: %v, %v", repos[2].IsLocal(), repos[3].IsLocal())
+	}
+	if !repos[4].IsLocal() || repos[4].LocalPath() != "/opt/artifacts" {
+		t.Errorf("expected local artifact path, got %q", repos[4].LocalPath())
+	}
+}
+
+func TestReadArtifact(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "lib-1.0.0.zip")
+	f, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatalf("failed to create zip: %v", err)
+	}
+	w := zip.NewWriter(f)
+	entry, err := w.Create("lib-1.0.0/composer.json")
+	if err != nil {
+		t.Fatalf("failed to add composer.json: %v", err)
+	}
+	entry.Write([]byte(`{"name": "acme/lib", "version": "1.0.0"}`))
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to write zip: %v", err)
+	}
+	f.Close()
+
+	c, err := ReadArtifact(zipPath)
+	if err != nil {
+		t.Fatalf("ReadArtifact failed: %v", err)
+	}
+	if c.Name != "acme/lib" || c.Version != "1.0.0" {
+		t.Errorf("unexpected artifact composer.json: %+v", c)
+	}
+}
+
+func TestVendorDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	root := Root(tmpDir, "app")
+	if root != filepath.Join(tmpDir, "app") {
+		t.Errorf("expected composer root below project, got %s", root)
+	}
+
+	if got := VendorDir(root); got != filepath.Join(root, "vendor") {
+		t.Errorf("expected default vendor dir, got %s", got)
+	}
+
+	if err := os.MkdirAll(root, 0755); err != nil {
+		t.Fatalf("failed to create composer root: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "composer.json"), []byte(`{"config": {"vendor-dir": "libs/php"}}`), 0644); err != nil {
+		t.Fatalf("failed to write composer.json: %v", err)
+	}
+	if got := VendorDir(root); got != filepath.Join(root, "libs", "php") {
+		t.Errorf("expected config.vendor-dir, got %s", got)
+	}
+}
+
+func TestParsePlatform(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"composer.json": `{"require": {"php": "^8.2"}, "config": {"platform": {"php": "8.2.0", "ext-redis": false}}}`,
+		"composer.lock": `{"packages": [], "platform": []}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	c, err := ParseComposerJSON(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseComposerJSON failed: %v", err)
+	}
+	if c.Config.Platform["php"] != "8.2.0" || len(c.Config.Platform) != 1 {
+		t.Errorf("expected config.platform with php only, got %v", c.Config.Platform)
+	}
+
+	lock, err := ParseLock(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseLock failed: %v", err)
+	}
+	if len(lock.Platform) != 0 {
+		t.Errorf("expected empty lock platform, got %v", lock.Platform)
+	}
+}
+
+func TestParseRepositories_ObjectForm(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	composerJSON := `{
+	"repositories": {
+		"local": {"type": "path", "url": "packages/*"},
+		"packagist.org": false,
+		"lib": {"type": "vcs", "url": "../lib"}
+	}
+}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "composer.json"), []byte(composerJSON), 0644); err != nil {
+		t.Fatalf("failed to write composer.json: %v", err)
+	}
+
+	repos, err := ParseRepositories(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseRepositories failed: %v", err)
+	}
+	if len(repos) != 2 || repos[0].URL != "packages/*" || repos[1].Type != "vcs" {
+		t.Errorf("expected path and vcs repositories in file order, got %+v", repos)
+	}
+}
@@ -0,0 +1,192 @@
+package composer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// version is a numeric version with up to four parts, e.g. 8.1.27
+type version [4]int
+
+var (
+	orSeparator = regexp.MustCompile(`\s*\|\|?\s*`)
+	hyphenRange = regexp.MustCompile(`^(\S+)\s+-\s+(\S+)$`)
+	operators   = []string{">=", "<=", "!=", "<>", "==", ">", "<", "=", "^", "~"}
+)
+
+// Satisfies reports whether version satisfies the Composer version
+// constraint, e.g. "^8.1 || ~7.4.0" or ">=8.1 <8.4". Stability flags and
+// suffixes such as @dev or -RC1 are ignored.
+func Satisfies(constraint, v string) (bool, error) {
+	parsed, _, err := parseVersion(v)
+	if err != nil {
+		return false, err
+	}
+
+	for _, group := range orSeparator.Split(strings.TrimSpace(constraint), -1) {
+		ok, err := satisfiesAll(group, parsed)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// satisfiesAll evaluates a hyphen range or a list of terms separated by
+// commas or spaces, all of which must match
+func satisfiesAll(group string, v version) (bool, error) {
+	if m := hyphenRange.FindStringSubmatch(group); m != nil {
+		lower, _, err := parseVersion(m[1])
+		if err != nil {
+			return false, err
+		}
+		upper, n, err := parseVersion(m[2])
+		if err != nil {
+			return false, err
+		}
+		// A partial upper bound includes all its releases: 1.0 - 2.1 is <2.2
+		if n < 3 {
+			return compare(v, lower) >= 0 && compare(v, bump(upper, n-1)) < 0, nil
+		}
+		return compare(v, lower) >= 0 && compare(v, upper) <= 0, nil
+	}
+
+	var terms []string
+	for _, field := range strings.FieldsFunc(group, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+		// Join operators written apart from their version, as in ">= 8.1"
+		if n := len(terms); n > 0 && isOperator(terms[n-1]) {
+			terms[n-1] += field
+			continue
+		}
+		terms = append(terms, field)
+	}
+	if len(terms) == 0 {
+		return false, fmt.Errorf("empty constraint")
+	}
+
+	for _, term := range terms {
+		ok, err := satisfiesTerm(term, v)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func satisfiesTerm(term string, v version) (bool, error) {
+	term, _, _ = strings.Cut(term, "@")
+	if term == "" || term == "*" || term == "x" || term == "X" {
+		return true, nil
+	}
+
+	op := ""
+	for _, candidate := range operators {
+		if strings.HasPrefix(term, candidate) {
+			op = candidate
+			break
+		}
+	}
+	rest := strings.TrimPrefix(term, op)
+
+	// Wildcards: 8.1.* is >=8.1 <8.2
+	for _, suffix := range []string{".*", ".x", ".X"} {
+		if prefix, ok := strings.CutSuffix(rest, suffix); ok {
+			lower, n, err := parseVersion(prefix)
+			if err != nil {
+				return false, err
+			}
+			return compare(v, lower) >= 0 && compare(v, bump(lower, n-1)) < 0, nil
+		}
+	}
+
+	c, n, err := parseVersion(rest)
+	if err != nil {
+		return false, err
+	}
+	switch op {
+	case ">=":
+		return compare(v, c) >= 0, nil
+	case "<=":
+		return compare(v, c) <= 0, nil
+	case ">":
+		return compare(v, c) > 0, nil
+	case "<":
+		return compare(v, c) < 0, nil
+	case "!=", "<>":
+		return compare(v, c) != 0, nil
+	case "^":
+		// The first non-zero part given may not change: ^0.3 is <0.4
+		i := 0
+		if c[0] == 0 && n > 1 {
+			i = 1
+			if c[1] == 0 && n > 2 {
+				i = 2
+			}
+		}
+		return compare(v, c) >= 0 && compare(v, bump(c, i)) < 0, nil
+	case "~":
+		// The last part given may increase: ~1.2 is <2.0, ~1.2.3 is <1.3
+		i := max(n-2, 0)
+		return compare(v, c) >= 0 && compare(v, bump(c, i)) < 0, nil
+	default:
+		return compare(v, c) == 0, nil
+	}
+}
+
+func isOperator(s string) bool {
+	for _, op := range operators {
+		if s == op {
+			return true
+		}
+	}
+	return false
+}
+
+// parseVersion parses a version like v8.1.2-RC1 and returns the number of
+// parts given
+func parseVersion(s string) (version, int, error) {
+	var v version
+
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "v"), "V")
+	trimmed, _, _ = strings.Cut(trimmed, "-")
+	trimmed, _, _ = strings.Cut(trimmed, "+")
+
+	parts := strings.Split(trimmed, ".")
+	if len(parts) > len(v) {
+		return v, 0, fmt.Errorf("invalid version %q", s)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, 0, fmt.Errorf("invalid version %q", s)
+		}
+		v[i] = n
+	}
+	return v, len(parts), nil
+}
+
+// bump increments part i of v and resets the parts after it
+func bump(v version, i int) version {
+	v[i]++
+	for j := i + 1; j < len(v); j++ {
+		v[j] = 0
+	}
+	return v
+}
+
+func compare(a, b version) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,50 @@
+package composer
+
+import "testing"
+
+func TestSatisfies(t *testing.T) {
+	cases := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{"^8.2", "8.2.10", true},
+		{"^8.2", "8.1.27", false},
+		{"^8.2", "9.0.0", false},
+		{"^0.3", "0.4.0", false},
+		{"~8.1", "8.3.0", true},
+		{"~8.1.0", "8.2.0", false},
+		{">=8.1 <8.4", "8.3.5", true},
+		{">= 8.1, <8.3", "8.3.0", false},
+		{"^7.4 || ^8.0", "7.4.33", true},
+		{"^7.4|^8.0", "8.3.0", true},
+		{"8.1.*", "8.1.2", true},
+		{"8.1.*", "8.2.0", false},
+		{"*", "5.6.0", true},
+		{"8.1 - 8.2", "8.2.9", true},
+		{"8.1 - 8.2", "8.3.0", false},
+		{"^8.1@dev", "8.1.0", true},
+		{">=8.2.0-dev", "8.1.99", false},
+		{"!=8.1.0", "8.1.0", false},
+		{"8.1", "8.1.0", true},
+	}
+	for _, c := range cases {
+		got, err := Satisfies(c.constraint, c.version)
+		if err != nil {
+			t.Errorf("Satisfies(%q, %q) failed: %v", c.constraint, c.version, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("expected Satisfies(%q, %q) to be %v, got %v", c.constraint, c.version, c.want, got)
+		}
+	}
+}
+
+func TestSatisfies_Invalid(t *testing.T) {
+	if _, err := Satisfies("dev-main", "8.1.0"); err == nil {
+		t.Error("expected error for branch constraint")
+	}
+	if _, err := Satisfies("^8.1", "eight"); err == nil {
+		t.Error("expected error for invalid version")
+	}
+}
@@ -0,0 +1,76 @@
+package composer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// Lock represents the parts of composer.lock ddev-explain uses
+type Lock struct {
+	Packages    []LockPackage `json:"packages"`
+	PackagesDev []LockPackage `json:"packages-dev"`
+	Platform    Platform      `json:"platform"`
+}
+
+// LockPackage is an installed package in composer.lock
+type LockPackage struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version"`
+	Type    string      `json:"type"`
+	Source  *LockSource `json:"source"`
+	Dist    *LockSource `json:"dist"`
+}
+
+// LockSource is the source or dist entry of a locked package
+type LockSource struct {
+	Type      string `json:"type"`
+	URL       string `json:"url"`
+	Reference string `json:"reference"`
+}
+
+// ParseLock reads composer.lock from a project. A missing lock file
+// returns nil without error.
+func ParseLock(projectPath string) (*Lock, error) {
+	data, err := os.ReadFile(filepath.Join(projectPath, "composer.lock"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var lock Lock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+	return &lock, nil
+}
+
+// Find returns the locked package with the given name from packages or
+// packages-dev
+func (l *Lock) Find(name string) (LockPackage, bool) {
+	for _, list := range [][]LockPackage{l.Packages, l.PackagesDev} {
+		for _, p := range list {
+			if p.Name == name {
+				return p, true
+			}
+		}
+	}
+	return LockPackage{}, false
+}
+
+// InstalledFrom returns how the package was installed: "path" for path
+// repositories, otherwise the dist type (zip, tar, ...) or source type (git, ...)
+func (p LockPackage) InstalledFrom() string {
+	if (p.Dist != nil && p.Dist.Type == "path") || (p.Source != nil && p.Source.Type == "path") {
+		return "path"
+	}
+	if p.Dist != nil && p.Dist.Type != "" {
+		return p.Dist.Type
+	}
+	if p.Source != nil {
+		return p.Source.Type
+	}
+	return ""
+}
@@ -0,0 +1,217 @@
+package ddev
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// generatedMarker is the comment DDEV and add-ons put in files they own
+const generatedMarker = "#ddev-generated"
+
+// knownAddonRepositories maps add-on names to their upstream repository,
+// used when an add-on was installed without addon-metadata
+var knownAddonRepositories = map[string]string{
+	"redis":           "ddev/ddev-redis",
+	"redis-commander": "ddev/ddev-redis-commander",
+	"solr":            "ddev/ddev-solr",
+	"elasticsearch":   "ddev/ddev-elasticsearch",
+	"opensearch":      "ddev/ddev-opensearch",
+	"adminer":         "ddev/ddev-adminer",
+	"phpmyadmin":      "ddev/ddev-phpmyadmin",
+	"memcached":       "ddev/ddev-memcached",
+	"varnish":         "ddev/ddev-varnish",
+	"rabbitmq":        "ddev/ddev-rabbitmq",
+	"mongo":           "ddev/ddev-mongo",
+	"minio":           "oblakstudio/ddev-minio",
+	"cron":            "ddev/ddev-cron",
+	"browsersync":     "ddev/ddev-browsersync",
+	"selenium":        "ddev/ddev-selenium-standalone-chrome",
+	"typesense":       "kevinquillen/ddev-typesense",
+	"meilisearch":     "kevinquillen/ddev-meilisearch",
+}
+
+// addonManifest represents .ddev/addon-metadata/<name>/manifest.yaml
+type addonManifest struct {
+	Name         string   `yaml:"name"`
+	Repository   string   `yaml:"repository"`
+	Version      string   `yaml:"version"`
+	InstallDate  string   `yaml:"install_date"`
+	ProjectFiles []string `yaml:"project_files"`
+}
+
+// DetectAddons finds installed DDEV add-ons from addon-metadata manifests.
+// Add-ons without metadata are inferred from #ddev-generated compose files.
+func DetectAddons(projectPath string) ([]model.Addon, error) {
+	ddevDir := filepath.Join(projectPath, ".ddev")
+
+	addons, err := addonsFromMetadata(ddevDir)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make(map[string]bool)
+	for _, addon := range addons {
+		owned[addon.Name] = true
+		for _, f := range addon.Files {
+			owned[f] = true
+		}
+	}
+
+	for _, addon := range inferAddons(ddevDir) {
+		if !owned[addon.Name] && !owned[addon.Files[0]] {
+			addons = append(addons, addon)
+		}
+	}
+
+	sort.Slice(addons, func(i, j int) bool { return addons[i].Name < addons[j].Name })
+	return addons, nil
+}
+
+func addonsFromMetadata(ddevDir string) ([]model.Addon, error) {
+	var addons []model.Addon
+
+	manifests, err := filepath.Glob(filepath.Join(ddevDir, "addon-metadata", "*", "manifest.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range manifests {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var manifest addonManifest
+		if err := yaml.Unmarshal(data, &manifest); err != nil {
+			continue
+		}
+		if manifest.Name == "" {
+			manifest.Name = filepath.Base(filepath.Dir(path))
+		}
+
+		addons = append(addons, model.Addon{
+			Name:       manifest.Name,
+			Repository: manifest.Repository,
+			Version:    manifest.Version,
+			Files:      manifest.ProjectFiles,
+			Source:     "manifest",
+		})
+	}
+
+	return addons, nil
+}
+
+// inferAddons treats every generated docker-compose.<name>.yaml as an
+// add-on and claims the generated files belonging to <name>
+func inferAddons(ddevDir string) []model.Addon {
+	var addons []model.Addon
+
+	composeFiles, _ := filepath.Glob(filepath.Join(ddevDir, "docker-compose.*.y*ml"))
+	sort.Strings(composeFiles)
+
+	for _, composePath := range composeFiles {
+		if !hasGeneratedMarker(composePath) {
+			continue
+		}
+
+		base := filepath.Base(composePath)
+		name := strings.TrimPrefix(base, "docker-compose.")
+		name = strings.TrimSuffix(strings.TrimSuffix(name, ".yaml"), ".yml")
+
+		files := []string{base}
+		files = append(files, generatedFilesUnder(ddevDir, name)...)
+		files = append(files, generatedFilesUnder(ddevDir, filepath.Join("commands", name))...)
+
+		// Commands named after the add-on in the shared host/web folders
+		for _, dir := range []string{"host", "web"} {
+			matches, _ := filepath.Glob(filepath.Join(ddevDir, "commands", dir, name+"*"))
+			for _, m := range matches {
+				if hasGeneratedMarker(m) {
+					rel, _ := filepath.Rel(ddevDir, m)
+					files = append(files, rel)
+				}
+			}
+		}
+
+		addons = append(addons, model.Addon{
+			Name:       name,
+			Repository: knownAddonRepositories[name],
+			Files:      files,
+			Source:     "inferred",
+		})
+	}
+
+	return addons
+}
+
+// generatedFilesUnder lists files below ddevDir/rel that carry the marker
+func generatedFilesUnder(ddevDir, rel string) []string {
+	var files []string
+	root := filepath.Join(ddevDir, rel)
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return files
+	}
+
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		if hasGeneratedMarker(path) {
+			relPath, _ := filepath.Rel(ddevDir, path)
+			files = append(files, relPath)
+		}
+		return nil
+	})
+
+	return files
+}
+
+// hasGeneratedMarker reports whether a file contains #ddev-generated
+func hasGeneratedMarker(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return bytes.Contains(data, []byte(generatedMarker))
+}
+
+// assignAddons links services and commands to the add-on owning their
+// files and records them on the add-on
+func assignAddons(project *model.Project, addons []model.Addon) {
+	ddevDir := filepath.Join(project.Path, ".ddev")
+
+	owner := make(map[string]int)
+	for i, addon := range addons {
+		for _, f := range addon.Files {
+			owner[filepath.Clean(f)] = i
+		}
+	}
+
+	for i := range project.Services {
+		svc := &project.Services[i]
+		if idx, ok := owner[svc.File]; ok && svc.File != "" {
+			svc.Addon = addons[idx].Name
+			addons[idx].Services = append(addons[idx].Services, svc.Name)
+		}
+	}
+
+	for i := range project.Commands {
+		cmd := &project.Commands[i]
+		rel, err := filepath.Rel(ddevDir, cmd.Path)
+		if err != nil {
+			continue
+		}
+		if idx, ok := owner[rel]; ok {
+			cmd.Addon = addons[idx].Name
+			addons[idx].Commands = append(addons[idx].Commands, cmd.Name)
+		}
+	}
+
+	project.Addons = addons
+}
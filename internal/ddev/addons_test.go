@@ -0,0 +1,88 @@
+package ddev
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseConfig_Addons(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"config.yaml": "name: test-project\n",
+		"docker-compose.redis.yaml": `#ddev-generated
+services:
+  redis:
+    image: redis:7
+`,
+		"docker-compose.solr.yaml": `#ddev-generated
+services:
+  solr:
+    image: solr:9
+`,
+		"docker-compose.mailpit.yaml": `services:
+  mailpit:
+    image: axllent/mailpit
+`,
+	})
+	ddevDir := filepath.Join(tmpDir, ".ddev")
+	files := map[string]string{
+		"addon-metadata/redis/manifest.yaml": `name: redis
+repository: ddev/ddev-redis
+version: v1.0.4
+project_files:
+  - docker-compose.redis.yaml
+  - commands/host/redis-flush
+`,
+		"commands/host/redis-flush": "#!/bin/bash\n#ddev-generated\n## Description: Flush redis\n",
+		"commands/host/solr-admin":  "#!/bin/bash\n#ddev-generated\n## Description: Open Solr admin\n",
+		"commands/host/fix":         "#!/bin/bash\n## Description: Fix code style\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(ddevDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	cfg, err := ParseConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	if len(cfg.Addons) != 2 {
+		t.Fatalf("expected 2 add-ons, got %+v", cfg.Addons)
+	}
+
+	redis := cfg.Addons[0]
+	if redis.Name != "redis" || redis.Source != "manifest" || redis.Version != "v1.0.4" {
+		t.Errorf("unexpected redis add-on: %+v", redis)
+	}
+	if len(redis.Services) != 1 || redis.Services[0] != "redis" {
+		t.Errorf("expected redis service to belong to redis, got %v", redis.Services)
+	}
+	if len(redis.Commands) != 1 || redis.Commands[0] != "redis-flush" {
+		t.Errorf("expected redis-flush command to belong to redis, got %v", redis.Commands)
+	}
+
+	solr := cfg.Addons[1]
+	if solr.Name != "solr" || solr.Source != "inferred" || solr.Repository != "ddev/ddev-solr" {
+		t.Errorf("unexpected solr add-on: %+v", solr)
+	}
+	if len(solr.Commands) != 1 || solr.Commands[0] != "solr-admin" {
+		t.Errorf("expected solr-admin command to belong to solr, got %v", solr.Commands)
+	}
+
+	for _, svc := range cfg.Services {
+		if svc.Name == "mailpit" && svc.Addon != "" {
+			t.Errorf("expected mailpit to be a custom service, got add-on %s", svc.Addon)
+		}
+	}
+	for _, cmd := range cfg.Commands {
+		if cmd.Name == "fix" && cmd.Addon != "" {
+			t.Errorf("expected fix to be a custom command, got add-on %s", cmd.Addon)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package ddev
 
 import (
 	"bufio"
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,56 +11,254 @@ import (
 	"github.com/dkd-dobberkau/ddev-explain/internal/model"
 )
 
-// DetectCommands finds custom DDEV commands in .ddev/commands/
+// builtinCommands are the commands the ddev binary provides itself. DDEV
+// ignores custom commands with these names.
+var builtinCommands = map[string]bool{
+	"add-on": true, "aliases": true, "auth": true, "blackfire": true, "clean": true,
+	"completion": true, "composer": true, "config": true, "debug": true, "delete": true,
+	"describe": true, "dotenv": true, "exec": true, "export-db": true, "get": true,
+	"help": true, "hostname": true, "import-db": true, "import-files": true, "list": true,
+	"logs": true, "mutagen": true, "npm": true, "pause": true, "poweroff": true,
+	"pull": true, "push": true, "restart": true, "self-upgrade": true, "service": true,
+	"share": true, "snapshot": true, "ssh": true, "start": true, "stop": true,
+	"utility": true, "version": true, "xdebug": true, "xhprof": true, "yarn": true,
+}
+
+// DetectCommands finds custom DDEV commands in .ddev/commands/<container>/
 func DetectCommands(projectPath string) ([]model.Command, error) {
+	return detectCommandsIn(filepath.Join(projectPath, ".ddev", "commands"), "project")
+}
+
+// DetectGlobalCommands finds global custom commands in <globalDir>/commands/<container>/
+func DetectGlobalCommands(globalDir string) ([]model.Command, error) {
+	return detectCommandsIn(filepath.Join(globalDir, "commands"), "global")
+}
+
+func detectCommandsIn(commandsDir, scope string) ([]model.Command, error) {
 	var commands []model.Command
 
-	commandsDir := filepath.Join(projectPath, ".ddev", "commands")
-	if _, err := os.Stat(commandsDir); os.IsNotExist(err) {
+	containers, err := os.ReadDir(commandsDir)
+	if os.IsNotExist(err) {
 		return commands, nil
 	}
+	if err != nil {
+		return commands, err
+	}
 
-	// Walk through host and web subdirectories
-	for _, subdir := range []string{"host", "web"} {
-		dir := filepath.Join(commandsDir, subdir)
+	// Each subdirectory is named after the container its commands run in
+	for _, container := range containers {
+		if !container.IsDir() {
+			continue
+		}
+
+		dir := filepath.Join(commandsDir, container.Name())
 		entries, err := os.ReadDir(dir)
 		if err != nil {
 			continue
 		}
 
 		for _, entry := range entries {
-			if entry.IsDir() {
+			if entry.IsDir() || !isCommandFile(entry.Name()) {
 				continue
 			}
 
 			cmdPath := filepath.Join(dir, entry.Name())
-			description := parseCommandDescription(cmdPath)
+			cmd := model.Command{
+				Name:      entry.Name(),
+				Container: container.Name(),
+				Scope:     scope,
+				Path:      cmdPath,
+				Generated: hasGeneratedMarker(cmdPath),
+			}
+			parseCommandAnnotations(cmdPath, &cmd)
 
-			commands = append(commands, model.Command{
-				Name:        entry.Name(),
-				Description: description,
-				Path:        cmdPath,
-			})
+			commands = append(commands, cmd)
 		}
 	}
 
 	return commands, nil
 }
 
-func parseCommandDescription(filePath string) string {
+// isCommandFile reports whether DDEV treats a file in a commands directory
+// as a command. It skips the README.txt and *.example files ddev config
+// installs, and dotfiles.
+func isCommandFile(name string) bool {
+	return !strings.HasPrefix(name, ".") && !strings.HasPrefix(name, "README") &&
+		!strings.HasSuffix(name, ".example")
+}
+
+// mergeCommands combines project and global commands the way DDEV offers
+// them: project commands shadow global ones of the same name, and commands
+// named like a built-in are ignored
+func mergeCommands(project, global []model.Command) ([]model.Command, []string) {
+	var warnings []string
+
+	projectNames := make(map[string]bool)
+	for _, cmd := range project {
+		projectNames[cmd.Name] = true
+	}
+	globalNames := make(map[string]bool)
+	for _, cmd := range global {
+		globalNames[cmd.Name] = true
+	}
+
+	commands := make([]model.Command, 0, len(project)+len(global))
+	for _, cmd := range project {
+		cmd.Shadows = globalNames[cmd.Name]
+		commands = append(commands, cmd)
+	}
+	for _, cmd := range global {
+		if projectNames[cmd.Name] {
+			cmd.Hidden = "shadowed by project command"
+		}
+		commands = append(commands, cmd)
+	}
+
+	for i := range commands {
+		if builtinCommands[commands[i].Name] {
+			commands[i].Hidden = "conflicts with built-in ddev " + commands[i].Name
+			warnings = append(warnings, fmt.Sprintf("%s command %q has the name of a built-in command and is ignored", commands[i].Scope, commands[i].Name))
+		}
+	}
+
+	return commands, warnings
+}
+
+// commandWarnings reports commands whose container no service defines
+func commandWarnings(project *model.Project) []string {
+	defined := map[string]bool{"host": true, "web": true, "db": true}
+	for _, omitted := range project.OmitContainers {
+		delete(defined, omitted)
+	}
+	for _, svc := range project.Services {
+		if svc.Type != "web-daemon" && svc.Type != "web-port" {
+			defined[svc.Name] = true
+		}
+	}
+
+	var warnings []string
+	for _, cmd := range project.Commands {
+		// Global commands for other services are simply not offered
+		if cmd.Scope == "project" && !defined[cmd.Container] {
+			warnings = append(warnings, fmt.Sprintf("command %q runs in container %q, which no service defines", cmd.Name, cmd.Container))
+		}
+	}
+	return warnings
+}
+
+// parseCommandAnnotations reads the "## Key: value" annotations DDEV uses
+// to describe a custom command
+func parseCommandAnnotations(filePath string, cmd *model.Command) {
 	file, err := os.Open(filePath)
 	if err != nil {
-		return ""
+		return
 	}
 	defer file.Close()
 
+	seen := make(map[string]bool)
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := scanner.Text()
-		if strings.HasPrefix(line, "## Description:") {
-			return strings.TrimSpace(strings.TrimPrefix(line, "## Description:"))
+		if !strings.HasPrefix(line, "## ") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "## "), ":")
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "Description":
+			cmd.Description = value
+		case "Usage":
+			cmd.Usage = value
+		case "Example":
+			// Multiple examples are separated by a literal \n
+			cmd.Example = strings.ReplaceAll(value, `\n`, "\n")
+		case "Flags":
+			cmd.Flags, err = parseCommandFlags(value)
+			if err != nil {
+				cmd.FlagsError = err.Error()
+			}
+		case "ProjectTypes":
+			cmd.ProjectTypes = splitList(value)
+		case "OSTypes":
+			cmd.OSTypes = splitList(value)
+		case "HostBinaryExists":
+			cmd.HostBinaryExists = splitList(value)
+		case "HostWorkingDir":
+			cmd.HostWorkingDir = value == "true"
+		case "CanRunGlobally":
+			cmd.CanRunGlobally = value == "true"
+		case "AutocompleteTerms":
+			if err := json.Unmarshal([]byte(value), &cmd.AutocompleteTerms); err != nil {
+				cmd.AutocompleteTerms = nil
+			}
+		}
+	}
+}
+
+// commandFlag mirrors the JSON DDEV expects in ## Flags:
+type commandFlag struct {
+	Name      string
+	Shorthand string
+	Usage     string
+	Type      string
+	DefValue  string
+}
+
+func parseCommandFlags(value string) ([]model.CommandFlag, error) {
+	var raw []commandFlag
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		return nil, fmt.Errorf("invalid Flags JSON: %w", err)
+	}
+
+	flags := make([]model.CommandFlag, 0, len(raw))
+	for _, f := range raw {
+		if f.Name == "" {
+			return nil, fmt.Errorf("invalid Flags JSON: flag without Name")
 		}
+		flags = append(flags, model.CommandFlag{
+			Name:      f.Name,
+			Shorthand: f.Shorthand,
+			Usage:     f.Usage,
+			Type:      f.Type,
+			Default:   f.DefValue,
+		})
 	}
+	return flags, nil
+}
 
+// splitList splits a comma-separated annotation value
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// hiddenReason explains why DDEV will not offer a command in this project,
+// or returns "" if the command is available
+func hiddenReason(cmd model.Command, projectType, goos string) string {
+	if len(cmd.ProjectTypes) > 0 && !contains(cmd.ProjectTypes, projectType) {
+		return fmt.Sprintf("only for project types %s", strings.Join(cmd.ProjectTypes, ", "))
+	}
+	if len(cmd.OSTypes) > 0 && !contains(cmd.OSTypes, goos) {
+		return fmt.Sprintf("only on %s", strings.Join(cmd.OSTypes, ", "))
+	}
 	return ""
 }
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,237 @@
+package ddev
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/model"
+)
+
+func TestParseCommandAnnotations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fix")
+	script := `#!/bin/bash
+## Description: Fix code style
+## Usage: fix [path]
+## Example: "ddev fix"\n"ddev fix src/"
+## Flags: [{"Name":"dry-run","Shorthand":"n","Usage":"Only show changes","Type":"bool","DefValue":"false"}]
+## ProjectTypes: drupal10, laravel
+## OSTypes: linux,darwin
+## HostBinaryExists: /usr/local/bin/php-cs-fixer
+## HostWorkingDir: true
+## CanRunGlobally: true
+## AutocompleteTerms: ["src","tests"]
+php-cs-fixer fix "$@"
+`
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write command: %v", err)
+	}
+
+	var cmd model.Command
+	parseCommandAnnotations(path, &cmd)
+
+	if cmd.Description != "Fix code style" || cmd.Usage != "fix [path]" {
+		t.Errorf("unexpected description/usage: %q / %q", cmd.Description, cmd.Usage)
+	}
+	if cmd.Example != "\"ddev fix\"\n\"ddev fix src/\"" {
+		t.Errorf("expected two examples, got %q", cmd.Example)
+	}
+	if len(cmd.Flags) != 1 || cmd.Flags[0].Name != "dry-run" || cmd.Flags[0].Shorthand != "n" || cmd.Flags[0].Default != "false" {
+		t.Errorf("unexpected flags: %+v", cmd.Flags)
+	}
+	if len(cmd.ProjectTypes) != 2 || cmd.ProjectTypes[1] != "laravel" {
+		t.Errorf("expected 2 project types, got %v", cmd.ProjectTypes)
+	}
+	if len(cmd.OSTypes) != 2 || len(cmd.HostBinaryExists) != 1 {
+		t.Errorf("unexpected OS types or host binaries: %v %v", cmd.OSTypes, cmd.HostBinaryExists)
+	}
+	if !cmd.HostWorkingDir || !cmd.CanRunGlobally {
+		t.Error("expected HostWorkingDir and CanRunGlobally to be set")
+	}
+	if len(cmd.AutocompleteTerms) != 2 {
+		t.Errorf("expected 2 autocomplete terms, got %v", cmd.AutocompleteTerms)
+	}
+}
+
+func TestParseCommandAnnotations_InvalidFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broken")
+	if err := os.WriteFile(path, []byte("## Flags: [{\"Name\": oops}]\n"), 0755); err != nil {
+		t.Fatalf("failed to write command: %v", err)
+	}
+
+	var cmd model.Command
+	parseCommandAnnotations(path, &cmd)
+
+	if cmd.FlagsError == "" {
+		t.Error("expected invalid Flags JSON to be reported")
+	}
+	if len(cmd.Flags) != 0 {
+		t.Errorf("expected no flags, got %+v", cmd.Flags)
+	}
+}
+
+func TestHiddenReason(t *testing.T) {
+	cmd := model.Command{ProjectTypes: []string{"drupal10"}, OSTypes: []string{"darwin"}}
+
+	if reason := hiddenReason(cmd, "typo3", "darwin"); reason == "" {
+		t.Error("expected command to be hidden for other project types")
+	}
+	if reason := hiddenReason(cmd, "drupal10", "linux"); reason == "" {
+		t.Error("expected command to be hidden on other operating systems")
+	}
+	if reason := hiddenReason(cmd, "drupal10", "darwin"); reason != "" {
+		t.Errorf("expected command to be available, got %q", reason)
+	}
+}
+
+func TestParseConfig_CommandContainers(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"config.yaml":                "name: test-project\n",
+		"docker-compose.solr.yaml":   "services:\n  solr:\n    image: solr:9\n",
+		"docker-compose.custom.yaml": "services:\n  web:\n    environment:\n      - FOO=bar\n",
+	})
+	for _, path := range []string{"host/open", "web/composer-fix", "db/dump", "solr/solrctl", "mongo/mongosh"} {
+		full := filepath.Join(tmpDir, ".ddev", "commands", path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatalf("failed to create directory for %s: %v", path, err)
+		}
+		if err := os.WriteFile(full, []byte("#!/bin/bash\n"), 0755); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	cfg, err := ParseConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	containers := make(map[string]string)
+	for _, cmd := range cfg.Commands {
+		containers[cmd.Name] = cmd.Container
+	}
+	expected := map[string]string{"open": "host", "composer-fix": "web", "dump": "db", "solrctl": "solr", "mongosh": "mongo"}
+	for name, container := range expected {
+		if containers[name] != container {
+			t.Errorf("expected %s to run in %s, got %q", name, container, containers[name])
+		}
+	}
+
+	if len(cfg.Warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %v", cfg.Warnings)
+	}
+	if !strings.Contains(cfg.Warnings[0], "mongosh") {
+		t.Errorf("expected warning about mongosh, got %q", cfg.Warnings[0])
+	}
+}
+
+func TestParseConfig_SkipsStockCommandFiles(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{"config.yaml": "name: test-project\n"})
+	// The files ddev config installs next to a user-written command
+	for _, path := range []string{"host/README.txt", "web/README.txt", "web/.gitattributes", "db/mysqldump.example", "solr/README.txt", "solr/solrtail.example", "web/fix"} {
+		full := filepath.Join(tmpDir, ".ddev", "commands", path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatalf("failed to create directory for %s: %v", path, err)
+		}
+		if err := os.WriteFile(full, []byte("#!/bin/bash\n"), 0755); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	cfg, err := ParseConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	if len(cfg.Commands) != 1 || cfg.Commands[0].Name != "fix" {
+		t.Errorf("expected only the fix command, got %+v", cfg.Commands)
+	}
+	if len(cfg.Warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", cfg.Warnings)
+	}
+}
+
+func TestParseConfigWithOptions_GlobalCommands(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{"config.yaml": "name: test-project\n"})
+	globalDir := t.TempDir()
+
+	files := map[string]string{
+		filepath.Join(tmpDir, ".ddev", "commands", "host", "fix"): "## Description: project fix\n",
+		filepath.Join(globalDir, "commands", "host", "fix"):       "## Description: global fix\n",
+		filepath.Join(globalDir, "commands", "web", "php"):        "## Description: run php\n",
+		filepath.Join(globalDir, "commands", "host", "logs"):      "## Description: my logs\n",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory for %s: %v", path, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	cfg, err := ParseConfigWithOptions(tmpDir, Options{GlobalDir: globalDir})
+	if err != nil {
+		t.Fatalf("ParseConfigWithOptions failed: %v", err)
+	}
+
+	if len(cfg.Commands) != 4 {
+		t.Fatalf("expected 4 commands, got %+v", cfg.Commands)
+	}
+	for _, cmd := range cfg.Commands {
+		switch {
+		case cmd.Name == "fix" && cmd.Scope == "project":
+			if !cmd.Shadows || cmd.Hidden != "" {
+				t.Errorf("expected project fix to shadow the global one, got %+v", cmd)
+			}
+		case cmd.Name == "fix" && cmd.Scope == "global":
+			if cmd.Hidden == "" {
+				t.Error("expected global fix to be hidden")
+			}
+		case cmd.Name == "php":
+			if cmd.Scope != "global" || cmd.Container != "web" || cmd.Hidden != "" {
+				t.Errorf("unexpected global php command: %+v", cmd)
+			}
+		case cmd.Name == "logs":
+			if !strings.Contains(cmd.Hidden, "built-in") {
+				t.Errorf("expected logs to collide with the built-in command, got %q", cmd.Hidden)
+			}
+		}
+	}
+
+	if len(cfg.Warnings) != 1 || !strings.Contains(cfg.Warnings[0], "logs") {
+		t.Errorf("expected a warning about logs, got %v", cfg.Warnings)
+	}
+}
+
+func TestParseConfigWithOptions_GlobalStockCommandFiles(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{"config.yaml": "name: test-project\n"})
+	globalDir := t.TempDir()
+
+	for _, path := range []string{
+		filepath.Join(tmpDir, ".ddev", "commands", "web", "README.txt"),
+		filepath.Join(globalDir, "commands", "web", "README.txt"),
+		filepath.Join(globalDir, "commands", "host", "README.txt"),
+		filepath.Join(globalDir, "commands", "host", "heidisql.example"),
+		filepath.Join(globalDir, "commands", "host", "open"),
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory for %s: %v", path, err)
+		}
+		if err := os.WriteFile(path, []byte("#!/bin/bash\n"), 0755); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	cfg, err := ParseConfigWithOptions(tmpDir, Options{GlobalDir: globalDir})
+	if err != nil {
+		t.Fatalf("ParseConfigWithOptions failed: %v", err)
+	}
+
+	if len(cfg.Commands) != 1 || cfg.Commands[0].Name != "open" || cfg.Commands[0].Scope != "global" {
+		t.Fatalf("expected only the global open command, got %+v", cfg.Commands)
+	}
+	if cfg.Commands[0].Shadows || cfg.Commands[0].Hidden != "" {
+		t.Errorf("expected open to be offered as is, got %+v", cfg.Commands[0])
+	}
+}
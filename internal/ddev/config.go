@@ -1,24 +1,47 @@
 package ddev
 
 import (
+	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
+	"runtime"
 
+	"github.com/dkd-dobberkau/ddev-explain/internal/compose"
+	"github.com/dkd-dobberkau/ddev-explain/internal/finder"
 	"github.com/dkd-dobberkau/ddev-explain/internal/model"
-	"gopkg.in/yaml.v3"
 )
 
 // DDEVConfig represents the raw .ddev/config.yaml structure
 type DDEVConfig struct {
-	Name               string            `yaml:"name"`
-	Type               string            `yaml:"type"`
-	PHPVersion         string            `yaml:"php_version"`
-	WebserverType      string            `yaml:"webserver_type"`
-	Database           DatabaseConfig    `yaml:"database"`
-	NodeJSVersion      string            `yaml:"nodejs_version"`
-	Hooks              map[string][]Hook `yaml:"hooks"`
-	AdditionalServices []string          `yaml:"additional_services"`
+	Name                  string              `yaml:"name"`
+	Type                  string              `yaml:"type"`
+	PHPVersion            string              `yaml:"php_version"`
+	WebserverType         string              `yaml:"webserver_type"`
+	Database              DatabaseConfig      `yaml:"database"`
+	NodeJSVersion         string              `yaml:"nodejs_version"`
+	Hooks                 map[string][]Hook   `yaml:"hooks"`
+	AdditionalServices    []string            `yaml:"additional_services"`
+	ProjectTLD            string              `yaml:"project_tld"`
+	AdditionalHostnames   []string            `yaml:"additional_hostnames"`
+	AdditionalFQDNs       []string            `yaml:"additional_fqdns"`
+	RouterHTTPPort        string              `yaml:"router_http_port"`
+	RouterHTTPSPort       string              `yaml:"router_https_port"`
+	PerformanceMode       string              `yaml:"performance_mode"`
+	OmitContainers        []string            `yaml:"omit_containers"`
+	XdebugIDELocation     string              `yaml:"xdebug_ide_location"`
+	Docroot               string              `yaml:"docroot"`
+	ComposerVersion       string              `yaml:"composer_version"`
+	ComposerRoot          string              `yaml:"composer_root"`
+	XdebugEnabled         bool                `yaml:"xdebug_enabled"`
+	XHProfMode            string              `yaml:"xhprof_mode"`
+	UploadDirs            []string            `yaml:"upload_dirs"`
+	Timezone              string              `yaml:"timezone"`
+	CorepackEnable        bool                `yaml:"corepack_enable"`
+	WebimageExtraPackages []string            `yaml:"webimage_extra_packages"`
+	DBImageExtraPackages  []string            `yaml:"dbimage_extra_packages"`
+	WebEnvironment        []string            `yaml:"web_environment"`
+	WebExtraExposedPorts  []ExposedPortConfig `yaml:"web_extra_exposed_ports"`
+	WebExtraDaemons       []DaemonConfig      `yaml:"web_extra_daemons"`
 }
 
 // DatabaseConfig represents the database section in config.yaml
@@ -27,23 +50,79 @@ type DatabaseConfig struct {
 	Version string `yaml:"version"`
 }
 
-// Hook represents a hook command in config.yaml
+// ExposedPortConfig represents a web_extra_exposed_ports entry in config.yaml
+type ExposedPortConfig struct {
+	Name          string `yaml:"name"`
+	ContainerPort int    `yaml:"container_port"`
+	HTTPPort      int    `yaml:"http_port"`
+	HTTPSPort     int    `yaml:"https_port"`
+}
+
+// DaemonConfig represents a web_extra_daemons entry in config.yaml
+type DaemonConfig struct {
+	Name      string `yaml:"name"`
+	Command   string `yaml:"command"`
+	Directory string `yaml:"directory"`
+}
+
+// Hook represents a hook task in config.yaml
 type Hook struct {
 	Exec     string `yaml:"exec"`
 	ExecHost string `yaml:"exec-host"`
+	Composer string `yaml:"composer"`
+	Service  string `yaml:"service"` // Container for exec, default web
 }
 
-// ParseConfig reads and parses the DDEV config from a project directory
+// Options controls how the effective project config is resolved
+type Options struct {
+	GlobalDir   string // DDEV global config directory; empty skips global_config.yaml and global commands
+	DDEVVersion string // Selects the defaults table; overrides ddev_version_constraint
+}
+
+// ParseConfig reads and parses the DDEV config from a project directory.
+// config.yaml is merged with config.*.yaml and config.local.yaml the same
+// way DDEV builds its effective configuration.
 func ParseConfig(projectPath string) (*model.Project, error) {
-	configPath := filepath.Join(projectPath, ".ddev", "config.yaml")
+	return ParseConfigWithOptions(projectPath, Options{})
+}
+
+// ParseConfigWithOptions is like ParseConfig but also applies the defaults
+// from global_config.yaml in opts.GlobalDir beneath the project files
+func ParseConfigWithOptions(projectPath string, opts Options) (*model.Project, error) {
+	layers, err := loadConfigLayers(filepath.Join(projectPath, ".ddev"))
+	if err != nil {
+		return nil, err
+	}
 
-	data, err := os.ReadFile(configPath)
+	// DDEV's built-in defaults depend on the release the project targets
+	defaults, err := loadDefaults()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config: %w", err)
+		return nil, err
+	}
+	wantVersion := opts.DDEVVersion
+	if wantVersion == "" {
+		wantVersion = layerValue(layers, "ddev_version_constraint")
+	}
+	defaultsEntry := selectDefaults(defaults, wantVersion)
+
+	if opts.GlobalDir != "" {
+		global, err := loadGlobalLayer(opts.GlobalDir)
+		if err == nil {
+			layers = append([]configLayer{global}, layers...)
+		} else if !errors.Is(err, finder.ErrNoGlobalConfig) {
+			return nil, err
+		}
+	}
+
+	merger := newConfigMerger()
+	merger.merge(defaultsLayer(defaultsEntry))
+	for _, layer := range layers {
+		merger.merge(layer)
 	}
+	merger.applyDatabaseDefault(defaultsEntry)
 
 	var cfg DDEVConfig
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
+	if err := merger.root.Decode(&cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
 
@@ -57,32 +136,93 @@ func ParseConfig(projectPath string) (*model.Project, error) {
 			Type:    cfg.Database.Type,
 			Version: cfg.Database.Version,
 		},
+		URLs:   buildURLs(cfg),
 		NodeJS: cfg.NodeJSVersion,
-		Hooks:  make(map[string][]string),
+
+		Docroot:               cfg.Docroot,
+		ComposerVersion:       cfg.ComposerVersion,
+		ComposerRoot:          cfg.ComposerRoot,
+		XdebugEnabled:         cfg.XdebugEnabled,
+		XHProfMode:            cfg.XHProfMode,
+		PerformanceMode:       cfg.PerformanceMode,
+		UploadDirs:            cfg.UploadDirs,
+		Timezone:              cfg.Timezone,
+		CorepackEnable:        cfg.CorepackEnable,
+		WebimageExtraPackages: cfg.WebimageExtraPackages,
+		DBImageExtraPackages:  cfg.DBImageExtraPackages,
+		WebEnvironment:        cfg.WebEnvironment,
+		OmitContainers:        cfg.OmitContainers,
+		XdebugIDELocation:     cfg.XdebugIDELocation,
+		RouterHTTPPort:        orDefault(cfg.RouterHTTPPort, defaultRouterHTTPPort),
+		RouterHTTPSPort:       orDefault(cfg.RouterHTTPSPort, defaultRouterHTTPSPort),
+
+		Provenance: merger.provenance,
 	}
 
-	// Convert hooks
-	for hookName, hooks := range cfg.Hooks {
-		for _, h := range hooks {
-			if h.Exec != "" {
-				project.Hooks[hookName] = append(project.Hooks[hookName], h.Exec)
-			}
-			if h.ExecHost != "" {
-				project.Hooks[hookName] = append(project.Hooks[hookName], "(host) "+h.ExecHost)
-			}
+	for _, p := range cfg.WebExtraExposedPorts {
+		project.WebExtraExposedPorts = append(project.WebExtraExposedPorts, model.ExposedPort{
+			Name:          p.Name,
+			ContainerPort: p.ContainerPort,
+			HTTPPort:      p.HTTPPort,
+			HTTPSPort:     p.HTTPSPort,
+		})
+	}
+	for _, d := range cfg.WebExtraDaemons {
+		project.WebExtraDaemons = append(project.WebExtraDaemons, model.Daemon{
+			Name:      d.Name,
+			Command:   d.Command,
+			Directory: d.Directory,
+		})
+	}
+
+	var webEnvironment []model.Origin
+	if node := mappingValue(merger.root, "web_environment"); node != nil {
+		for _, item := range node.Content {
+			webEnvironment = append(webEnvironment, merger.itemOrigin(item))
 		}
 	}
+	project.Environment = DetectEnvironment(projectPath, webEnvironment)
+	project.Providers = DetectProviders(projectPath, project.Environment)
+
+	project.ImageCustomizations = DetectImageCustomizations(projectPath)
+	project.Overrides = DetectOverrides(projectPath)
+
+	// Convert hooks
+	var hookWarnings []string
+	project.Hooks, hookWarnings = buildHooks(merger)
+	project.Warnings = append(project.Warnings, hookWarnings...)
 
 	// Detect services
-	services, err := DetectServices(projectPath)
+	services, composeWarnings, err := DetectServices(projectPath, compose.ProjectEnv(project))
 	if err == nil {
 		project.Services = services
+		project.Warnings = append(project.Warnings, composeWarnings...)
 	}
+	project.Services = append(project.Services, DetectWebDaemons(project.WebExtraDaemons, project.WebExtraExposedPorts)...)
 
 	// Detect commands
 	commands, err := DetectCommands(projectPath)
 	if err == nil {
-		project.Commands = commands
+		var globalCommands []model.Command
+		if opts.GlobalDir != "" {
+			globalCommands, _ = DetectGlobalCommands(opts.GlobalDir)
+		}
+		for i := range commands {
+			commands[i].Hidden = hiddenReason(commands[i], project.Type, runtime.GOOS)
+		}
+		for i := range globalCommands {
+			globalCommands[i].Hidden = hiddenReason(globalCommands[i], project.Type, runtime.GOOS)
+		}
+		var warnings []string
+		project.Commands, warnings = mergeCommands(commands, globalCommands)
+		project.Warnings = append(project.Warnings, warnings...)
+	}
+	project.Warnings = append(project.Warnings, commandWarnings(project)...)
+
+	// Group services and commands under the add-ons that installed them
+	addons, err := DetectAddons(projectPath)
+	if err == nil {
+		assignAddons(project, addons)
 	}
 
 	return project, nil
@@ -56,3 +56,422 @@ func TestParseConfig_MissingFile(t *testing.T) {
 		t.Error("expected error when config file is missing")
 	}
 }
+
+func writeConfigFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	ddevDir := filepath.Join(tmpDir, ".ddev")
+	if err := os.MkdirAll(ddevDir, 0755); err != nil {
+		t.Fatalf("failed to create test directory: %v", err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(ddevDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return tmpDir
+}
+
+func TestParseConfig_MergesOverrides(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"config.yaml": `name: test-project
+type: typo3
+php_version: "8.1"
+database:
+  type: mariadb
+  version: "10.4"
+hooks:
+  post-start:
+    - exec: composer install
+`,
+		"config.b-team.yaml": `php_version: "8.2"
+hooks:
+  post-start:
+    - exec: npm ci
+`,
+		"config.local.yaml": `php_version: "8.3"
+database:
+  version: "10.11"
+nodejs_version: ""
+`,
+	})
+
+	cfg, err := ParseConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	// config.local.yaml sorts after config.b-team.yaml and wins
+	if cfg.PHPVersion != "8.3" {
+		t.Errorf("expected PHP version '8.3', got '%s'", cfg.PHPVersion)
+	}
+	if cfg.Database.Type != "mariadb" {
+		t.Errorf("expected database type 'mariadb', got '%s'", cfg.Database.Type)
+	}
+	if cfg.Database.Version != "10.11" {
+		t.Errorf("expected database version '10.11', got '%s'", cfg.Database.Version)
+	}
+
+	hooks := cfg.Hooks["post-start"]
+	if len(hooks) != 2 || hooks[0].Command != "composer install" || hooks[1].Command != "npm ci" {
+		t.Errorf("expected appended post-start hooks, got %v", hooks)
+	}
+}
+
+func TestParseConfig_OverrideConfig(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"config.yaml": `name: test-project
+php_version: "8.1"
+hooks:
+  post-start:
+    - exec: composer install
+`,
+		"config.override.yaml": `override_config: true
+hooks:
+  post-start:
+    - exec: npm ci
+`,
+	})
+
+	cfg, err := ParseConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	hooks := cfg.Hooks["post-start"]
+	if len(hooks) != 1 || hooks[0].Command != "npm ci" {
+		t.Errorf("expected override_config to replace hooks, got %v", hooks)
+	}
+	if cfg.PHPVersion != "8.1" {
+		t.Errorf("expected PHP version '8.1' to survive, got '%s'", cfg.PHPVersion)
+	}
+}
+
+func TestParseConfig_ZeroValuesDoNotOverride(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"config.yaml": `name: test-project
+xdebug_enabled: true
+upload_dirs: [fileadmin]
+`,
+		"config.local.yaml": `xdebug_enabled: false
+upload_dirs: []
+`,
+	})
+
+	cfg, err := ParseConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if !cfg.XdebugEnabled {
+		t.Error("expected false in config.local.yaml not to override true")
+	}
+	if len(cfg.UploadDirs) != 1 {
+		t.Errorf("expected upload_dirs to survive an empty list, got %v", cfg.UploadDirs)
+	}
+	if chain := cfg.Provenance["xdebug_enabled"]; len(chain) != 1 || chain[0].File != "config.yaml" {
+		t.Errorf("expected only config.yaml in the provenance, got %+v", chain)
+	}
+
+	tmpDir = writeConfigFiles(t, map[string]string{
+		"config.yaml": `name: test-project
+xdebug_enabled: true
+`,
+		"config.local.yaml": `override_config: true
+xdebug_enabled: false
+`,
+	})
+	cfg, err = ParseConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if cfg.XdebugEnabled {
+		t.Error("expected override_config to let false override true")
+	}
+	if source, _ := cfg.Source("xdebug_enabled"); source.File != "config.local.yaml" || source.Mode != "replace" {
+		t.Errorf("expected config.local.yaml to replace the value, got %+v", source)
+	}
+}
+
+func TestParseConfig_Provenance(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"config.yaml": `name: test-project
+php_version: "8.1"
+database:
+  type: mariadb
+  version: "10.4"
+`,
+		"config.local.yaml": `# local overrides
+php_version: "8.3"
+`,
+	})
+
+	cfg, err := ParseConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	// The chain starts with DDEV's built-in default
+	chain := cfg.Provenance["php_version"]
+	if len(chain) != 3 {
+		t.Fatalf("expected 3 origins for php_version, got %d", len(chain))
+	}
+	if chain[0].Scope != "default" {
+		t.Errorf("expected first origin to be the DDEV default, got '%s'", chain[0].String())
+	}
+	if chain[1].String() != "config.yaml:2" {
+		t.Errorf("expected second origin 'config.yaml:2', got '%s'", chain[1].String())
+	}
+	if chain[2].String() != "config.local.yaml:2" || chain[2].Value != "8.3" {
+		t.Errorf("expected effective origin 'config.local.yaml:2' = 8.3, got '%s' = %s", chain[2].String(), chain[2].Value)
+	}
+
+	origin, ok := cfg.Source("database.version")
+	if !ok || origin.String() != "config.yaml:5" {
+		t.Errorf("expected database.version from 'config.yaml:5', got '%s'", origin.String())
+	}
+}
+
+func TestParseConfig_URLs(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"config.yaml": `name: shop
+project_tld: test
+additional_hostnames:
+  - "*.shop"
+  - api
+additional_fqdns:
+  - shop.example.com
+router_https_port: 8443
+`,
+	})
+
+	cfg, err := ParseConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	expected := []string{
+		"https://shop.test:8443",
+		"https://*.shop.test:8443",
+		"https://api.test:8443",
+		"https://shop.example.com:8443",
+		"http://shop.test",
+		"http://*.shop.test",
+		"http://api.test",
+		"http://shop.example.com",
+	}
+	if len(cfg.URLs) != len(expected) {
+		t.Fatalf("expected %d URLs, got %v", len(expected), cfg.URLs)
+	}
+	for i, u := range expected {
+		if cfg.URLs[i] != u {
+			t.Errorf("expected URL %d to be '%s', got '%s'", i, u, cfg.URLs[i])
+		}
+	}
+}
+
+func TestParseConfigWithOptions_GlobalConfig(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"config.yaml": `name: test-project
+omit_containers: [dba]
+`,
+	})
+
+	globalDir := t.TempDir()
+	globalConfig := `router_https_port: "8443"
+project_tld: test
+performance_mode: mutagen
+omit_containers: [ddev-ssh-agent]
+instrumentation_opt_in: false
+`
+	if err := os.WriteFile(filepath.Join(globalDir, "global_config.yaml"), []byte(globalConfig), 0644); err != nil {
+		t.Fatalf("failed to write global config: %v", err)
+	}
+
+	cfg, err := ParseConfigWithOptions(tmpDir, Options{GlobalDir: globalDir})
+	if err != nil {
+		t.Fatalf("ParseConfigWithOptions failed: %v", err)
+	}
+
+	if cfg.PerformanceMode != "mutagen" {
+		t.Errorf("expected performance mode 'mutagen', got '%s'", cfg.PerformanceMode)
+	}
+	if cfg.Inherited("performance_mode") != "global" {
+		t.Errorf("expected performance_mode to be inherited globally, got '%s'", cfg.Inherited("performance_mode"))
+	}
+	if len(cfg.URLs) == 0 || cfg.URLs[0] != "https://test-project.test:8443" {
+		t.Errorf("expected primary URL from global TLD and port, got %v", cfg.URLs)
+	}
+	if len(cfg.OmitContainers) != 2 {
+		t.Errorf("expected global and project omit_containers to combine, got %v", cfg.OmitContainers)
+	}
+	if _, ok := cfg.Provenance["instrumentation_opt_in"]; ok {
+		t.Error("expected global-only settings to be ignored")
+	}
+}
+
+func TestParseConfigWithOptions_MissingGlobalConfig(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"config.yaml": "name: test-project\n",
+	})
+
+	cfg, err := ParseConfigWithOptions(tmpDir, Options{GlobalDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("expected missing global config to be ignored, got %v", err)
+	}
+	if cfg.RouterHTTPSPort != "443" {
+		t.Errorf("expected default https port '443', got '%s'", cfg.RouterHTTPSPort)
+	}
+}
+
+func TestParseConfig_Defaults(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"config.yaml": `name: test-project
+database:
+  type: postgres
+`,
+	})
+
+	cfg, err := ParseConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	if cfg.PHPVersion != "8.3" {
+		t.Errorf("expected default PHP version '8.3', got '%s'", cfg.PHPVersion)
+	}
+	if cfg.Inherited("php_version") != "default" {
+		t.Errorf("expected php_version to be a default, got '%s'", cfg.Inherited("php_version"))
+	}
+	if cfg.Webserver != "nginx-fpm" {
+		t.Errorf("expected default webserver 'nginx-fpm', got '%s'", cfg.Webserver)
+	}
+	if cfg.Database.Type != "postgres" || cfg.Database.Version != "16" {
+		t.Errorf("expected postgres 16, got '%s %s'", cfg.Database.Type, cfg.Database.Version)
+	}
+	if cfg.Inherited("database.type") != "" {
+		t.Error("expected database.type to be set by the project")
+	}
+}
+
+func TestParseConfig_DefaultsFromVersionConstraint(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"config.yaml": `name: test-project
+ddev_version_constraint: ">= v1.22.3"
+`,
+	})
+
+	cfg, err := ParseConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if cfg.PHPVersion != "8.1" || cfg.Database.Version != "10.4" {
+		t.Errorf("expected v1.22 defaults PHP 8.1 / MariaDB 10.4, got %s / %s", cfg.PHPVersion, cfg.Database.Version)
+	}
+
+	cfg, err = ParseConfigWithOptions(tmpDir, Options{DDEVVersion: "v1.23.1"})
+	if err != nil {
+		t.Fatalf("ParseConfigWithOptions failed: %v", err)
+	}
+	if cfg.PHPVersion != "8.2" {
+		t.Errorf("expected --ddev-version to select PHP 8.2, got %s", cfg.PHPVersion)
+	}
+}
+
+func TestParseConfig_WebContainerSettings(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"config.yaml": `name: test-project
+docroot: public
+composer_version: "2"
+composer_root: app
+xdebug_enabled: true
+xhprof_mode: xhgui
+upload_dirs: [fileadmin, uploads]
+timezone: Europe/Berlin
+corepack_enable: true
+webimage_extra_packages: [php8.3-imagick]
+dbimage_extra_packages: [vim]
+web_environment:
+  - TYPO3_CONTEXT=Development
+web_extra_exposed_ports:
+  - name: vite
+    container_port: 5173
+    http_port: 5172
+    https_port: 5173
+web_extra_daemons:
+  - name: vite
+    command: npm run dev
+    directory: /var/www/html
+omit_containers: [dba]
+`,
+	})
+
+	cfg, err := ParseConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	if cfg.Docroot != "public" || cfg.ComposerRoot != "app" || cfg.XHProfMode != "xhgui" || cfg.Timezone != "Europe/Berlin" {
+		t.Errorf("unexpected scalar settings: %+v", cfg)
+	}
+	if !cfg.XdebugEnabled || !cfg.CorepackEnable {
+		t.Error("expected xdebug_enabled and corepack_enable to be true")
+	}
+	if len(cfg.UploadDirs) != 2 || len(cfg.WebimageExtraPackages) != 1 || len(cfg.DBImageExtraPackages) != 1 || len(cfg.WebEnvironment) != 1 {
+		t.Errorf("unexpected list settings: %+v", cfg)
+	}
+	if len(cfg.WebExtraExposedPorts) != 1 || cfg.WebExtraExposedPorts[0].HTTPSPort != 5173 {
+		t.Errorf("unexpected exposed ports: %+v", cfg.WebExtraExposedPorts)
+	}
+	if len(cfg.WebExtraDaemons) != 1 || cfg.WebExtraDaemons[0].Command != "npm run dev" {
+		t.Errorf("unexpected daemons: %+v", cfg.WebExtraDaemons)
+	}
+}
+
+func TestParseConfig_Environment(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"config.yaml": `name: test-project
+web_environment:
+  - APP_ENV=ddev
+`,
+		"config.local.yaml": `web_environment:
+  - DEBUG=1
+`,
+		".env": `APP_ENV=from-dotenv
+export API_TOKEN="abc"
+`,
+		".env.solr":    "SOLR_HEAP=1g\n",
+		".env.example": "IGNORED=1\n",
+	})
+
+	cfg, err := ParseConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	web := cfg.Environment["web"]
+	if len(web) != 3 {
+		t.Fatalf("expected 3 web variables, got %v", web)
+	}
+	byKey := make(map[string]string)
+	sources := make(map[string]string)
+	for _, v := range web {
+		byKey[v.Key] = v.Value
+		sources[v.Key] = v.Source
+	}
+	if byKey["APP_ENV"] != "ddev" || sources["APP_ENV"] != "config.yaml:3" {
+		t.Errorf("expected web_environment to win over .env, got %s from %s", byKey["APP_ENV"], sources["APP_ENV"])
+	}
+	if sources["DEBUG"] != "config.local.yaml:2" {
+		t.Errorf("expected DEBUG from config.local.yaml:2, got %s", sources["DEBUG"])
+	}
+	if byKey["API_TOKEN"] != "abc" || sources["API_TOKEN"] != ".env:2" {
+		t.Errorf("expected API_TOKEN from .env:2, got %s from %s", byKey["API_TOKEN"], sources["API_TOKEN"])
+	}
+
+	if len(cfg.Environment["solr"]) != 1 {
+		t.Errorf("expected 1 solr variable, got %v", cfg.Environment["solr"])
+	}
+	if _, ok := cfg.Environment["example"]; ok {
+		t.Error("expected .env.example to be ignored")
+	}
+}
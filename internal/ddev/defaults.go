@@ -0,0 +1,144 @@
+package ddev
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed defaults.yaml
+var defaultsYAML []byte
+
+var versionPattern = regexp.MustCompile(`(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+
+// defaultsEntry is the defaults table for one DDEV release
+type defaultsEntry struct {
+	Version  string
+	Settings *yaml.Node        // mapping node of config keys
+	DBVers   map[string]string // database type -> default version
+}
+
+// loadDefaults parses the embedded defaults table in file order
+func loadDefaults() ([]defaultsEntry, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(defaultsYAML, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse defaults table: %w", err)
+	}
+	root := doc.Content[0]
+
+	var entries []defaultsEntry
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		entry := defaultsEntry{
+			Version:  root.Content[i].Value,
+			Settings: &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"},
+		}
+
+		settings := root.Content[i+1]
+		for j := 0; j+1 < len(settings.Content); j += 2 {
+			key, val := settings.Content[j], settings.Content[j+1]
+			if key.Value == "database_versions" {
+				if err := val.Decode(&entry.DBVers); err != nil {
+					return nil, fmt.Errorf("failed to parse defaults table: %w", err)
+				}
+				continue
+			}
+			entry.Settings.Content = append(entry.Settings.Content, key, val)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// selectDefaults picks the newest defaults entry not newer than the version
+// named in want, e.g. "v1.23.5" or ">= v1.23.0". An empty want selects the
+// latest entry.
+func selectDefaults(entries []defaultsEntry, want string) defaultsEntry {
+	selected := entries[len(entries)-1]
+	target, ok := parseVersion(want)
+	if !ok {
+		return selected
+	}
+
+	selected = entries[0]
+	for _, entry := range entries {
+		v, _ := parseVersion(entry.Version)
+		if compareVersions(v, target) <= 0 {
+			selected = entry
+		}
+	}
+	return selected
+}
+
+// defaultsLayer turns a defaults entry into the lowest config layer
+func defaultsLayer(entry defaultsEntry) configLayer {
+	return configLayer{
+		Path:  fmt.Sprintf("DDEV %s defaults", entry.Version),
+		Root:  entry.Settings,
+		Scope: "default",
+	}
+}
+
+// applyDatabaseDefault fills database.version from the defaults entry when
+// no config file sets it. The default depends on the effective database type.
+func (m *configMerger) applyDatabaseDefault(entry defaultsEntry) {
+	db := mappingValue(m.root, "database")
+	if db == nil || db.Kind != yaml.MappingNode {
+		return
+	}
+	if v := mappingValue(db, "version"); v != nil && !isEmptyNode(v) {
+		return
+	}
+
+	dbType := mappingValue(db, "type")
+	if dbType == nil {
+		return
+	}
+	version, ok := entry.DBVers[dbType.Value]
+	if !ok {
+		return
+	}
+
+	db.Content = append(db.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"},
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: version},
+	)
+	m.provenance["database.version"] = append(m.provenance["database.version"], model.Origin{
+		File:  fmt.Sprintf("DDEV %s defaults", entry.Version),
+		Value: version,
+		Mode:  "set",
+		Scope: "default",
+	})
+}
+
+// parseVersion extracts the first major.minor.patch version from s
+func parseVersion(s string) ([3]int, bool) {
+	var v [3]int
+	m := versionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return v, false
+	}
+	for i := 0; i < 3; i++ {
+		if m[i+1] != "" {
+			v[i], _ = strconv.Atoi(m[i+1])
+		}
+	}
+	return v, true
+}
+
+func compareVersions(a, b [3]int) int {
+	for i := 0; i < 3; i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,77 @@
+package ddev
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/dotenv"
+	"github.com/dkd-dobberkau/ddev-explain/internal/model"
+)
+
+// DetectEnvironment collects the variables DDEV passes to each container:
+// .ddev/.env for web, .ddev/.env.<service> for other services, and
+// web_environment entries, which take precedence over .ddev/.env
+func DetectEnvironment(projectPath string, webEnvironment []model.Origin) map[string][]model.EnvVar {
+	vars := make(map[string]map[string]model.EnvVar)
+	set := func(service string, v model.EnvVar) {
+		if vars[service] == nil {
+			vars[service] = make(map[string]model.EnvVar)
+		}
+		vars[service][v.Key] = v
+	}
+
+	ddevDir := filepath.Join(projectPath, ".ddev")
+	entries, _ := os.ReadDir(ddevDir)
+	for _, entry := range entries {
+		service, ok := envFileService(entry.Name())
+		if entry.IsDir() || !ok {
+			continue
+		}
+
+		parsed, err := dotenv.ParseFile(filepath.Join(ddevDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		for _, e := range parsed {
+			set(service, model.EnvVar{
+				Key:    e.Key,
+				Value:  e.Value,
+				Source: model.Origin{File: entry.Name(), Line: e.Line}.String(),
+			})
+		}
+	}
+
+	for _, origin := range webEnvironment {
+		key, value, ok := strings.Cut(origin.Value, "=")
+		if !ok {
+			continue
+		}
+		set("web", model.EnvVar{Key: key, Value: value, Source: origin.String()})
+	}
+
+	result := make(map[string][]model.EnvVar)
+	for service, byKey := range vars {
+		for _, v := range byKey {
+			result[service] = append(result[service], v)
+		}
+		sort.Slice(result[service], func(i, j int) bool {
+			return result[service][i].Key < result[service][j].Key
+		})
+	}
+
+	return result
+}
+
+// envFileService maps .env to "web" and .env.<service> to the service name
+func envFileService(name string) (string, bool) {
+	if name == ".env" {
+		return "web", true
+	}
+	service, ok := strings.CutPrefix(name, ".env.")
+	if !ok || service == "" || service == "example" || strings.Contains(service, ".") {
+		return "", false
+	}
+	return service, true
+}
@@ -0,0 +1,113 @@
+package ddev
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/model"
+)
+
+// File states reported by ClassifyFiles
+const (
+	FileGenerated    = "generated"     // carries #ddev-generated, DDEV may overwrite it
+	FileUserModified = "user-modified" // no marker, DDEV leaves it alone
+	FileUnknown      = "unknown"       // binary or unreadable, the marker cannot apply
+)
+
+// runtimeEntries are files and directories DDEV rebuilds on every start;
+// they are not part of the project's configuration
+var runtimeEntries = map[string]bool{
+	".ddev-docker-compose-base.yaml": true,
+	".ddev-docker-compose-full.yaml": true,
+	".dbimageBuild":                  true,
+	".webimageBuild":                 true,
+	".sshimageBuild":                 true,
+	".global_commands":               true,
+	".downloads":                     true,
+	".importdb":                      true,
+	"db_snapshots":                   true,
+}
+
+// managedDirs are directories DDEV maintains. Only the files matching the
+// patterns are configuration, which a user takes over by removing
+// #ddev-generated; certificates and sync state around them are not.
+var managedDirs = map[string][]string{
+	"traefik": {"config/*.yaml", "static_config.*.yaml"},
+	"mutagen": {"mutagen.yml"},
+}
+
+// isRuntimeFile reports whether rel, relative to .ddev, lies in a managed
+// directory without being one of its configuration files
+func isRuntimeFile(rel string) bool {
+	dir, name, ok := strings.Cut(filepath.ToSlash(rel), "/")
+	patterns, managed := managedDirs[dir]
+	if !ok || !managed {
+		return false
+	}
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return false
+		}
+	}
+	return true
+}
+
+// ClassifyFiles walks the .ddev directory and reports for every file
+// whether DDEV generated it or the user wrote or edited it
+func ClassifyFiles(projectPath string) ([]model.DDEVFile, error) {
+	ddevDir := filepath.Join(projectPath, ".ddev")
+
+	var files []model.DDEVFile
+	err := filepath.Walk(ddevDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(ddevDir, path)
+		if err != nil || rel == "." {
+			return nil
+		}
+		if runtimeEntries[rel] {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() || !info.Mode().IsRegular() || isRuntimeFile(rel) {
+			return nil
+		}
+
+		status := classifyFile(path)
+		// DDEV writes add-on manifests without the marker
+		if strings.HasPrefix(filepath.ToSlash(rel), "addon-metadata/") && status != FileUnknown {
+			status = FileGenerated
+		}
+
+		files = append(files, model.DDEVFile{
+			Path:   rel,
+			Status: status,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// classifyFile returns the state of a single file
+func classifyFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil || bytes.IndexByte(data, 0) >= 0 {
+		return FileUnknown
+	}
+	if bytes.Contains(data, []byte(generatedMarker)) {
+		return FileGenerated
+	}
+	return FileUserModified
+}
@@ -0,0 +1,45 @@
+package ddev
+
+import (
+	"github.com/dkd-dobberkau/ddev-explain/internal/finder"
+	"gopkg.in/yaml.v3"
+)
+
+// globalProjectKeys are the global_config.yaml settings that act as
+// defaults for every project
+var globalProjectKeys = map[string]bool{
+	"router_http_port":    true,
+	"router_https_port":   true,
+	"project_tld":         true,
+	"performance_mode":    true,
+	"omit_containers":     true,
+	"xdebug_ide_location": true,
+	"web_environment":     true,
+}
+
+// loadGlobalLayer reads global_config.yaml from globalDir and keeps only the
+// keys that apply to projects. Returns finder.ErrNoGlobalConfig if the file
+// does not exist.
+func loadGlobalLayer(globalDir string) (configLayer, error) {
+	configPath, err := finder.GlobalConfigPath(globalDir)
+	if err != nil {
+		return configLayer{}, err
+	}
+
+	layer, err := loadConfigLayer(configPath)
+	if err != nil {
+		return layer, err
+	}
+
+	filtered := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for i := 0; i+1 < len(layer.Root.Content); i += 2 {
+		if globalProjectKeys[layer.Root.Content[i].Value] {
+			filtered.Content = append(filtered.Content, layer.Root.Content[i], layer.Root.Content[i+1])
+		}
+	}
+	layer.Root = filtered
+	layer.Override = false
+	layer.Scope = "global"
+
+	return layer, nil
+}
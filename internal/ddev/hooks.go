@@ -0,0 +1,121 @@
+package ddev
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// hookActions are the ddev actions that run pre-<action> and post-<action>
+// hooks, in the order they typically happen in a project's life
+var hookActions = []string{
+	"config", "start", "composer", "import-db", "import-files", "exec",
+	"pull", "push", "snapshot", "restore-snapshot", "delete-snapshot",
+	"share", "pause", "stop", "delete",
+}
+
+// HookActions returns the ddev actions that run hooks, in lifecycle order
+func HookActions() []string {
+	return append([]string(nil), hookActions...)
+}
+
+// HookEvents returns every hook event DDEV knows, pre before post, in
+// lifecycle order
+func HookEvents() []string {
+	events := make([]string, 0, 2*len(hookActions))
+	for _, action := range hookActions {
+		events = append(events, "pre-"+action, "post-"+action)
+	}
+	return events
+}
+
+// buildHooks turns the merged hooks mapping into structured entries, each
+// attributed to the config file that declared it
+func buildHooks(m *configMerger) (map[string][]model.Hook, []string) {
+	hooks := make(map[string][]model.Hook)
+	var warnings []string
+
+	node := mappingValue(m.root, "hooks")
+	if node == nil || node.Kind != yaml.MappingNode {
+		return hooks, warnings
+	}
+
+	known := make(map[string]bool)
+	for _, event := range HookEvents() {
+		known[event] = true
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		event, items := node.Content[i].Value, node.Content[i+1]
+		if items.Kind != yaml.SequenceNode {
+			continue
+		}
+
+		for _, item := range items.Content {
+			var h Hook
+			if err := item.Decode(&h); err != nil {
+				continue
+			}
+			source := m.itemOrigin(item).String()
+
+			container := h.Service
+			if container == "" {
+				container = "web"
+			}
+			if h.Exec != "" {
+				hooks[event] = append(hooks[event], model.Hook{Kind: "exec", Container: container, Command: h.Exec, Source: source})
+			}
+			if h.ExecHost != "" {
+				hooks[event] = append(hooks[event], model.Hook{Kind: "exec-host", Container: "host", Command: h.ExecHost, Source: source})
+			}
+			if h.Composer != "" {
+				hooks[event] = append(hooks[event], model.Hook{Kind: "composer", Container: "web", Command: h.Composer, Source: source})
+			}
+		}
+
+		if !known[event] && len(hooks[event]) > 0 {
+			warning := fmt.Sprintf("unknown hook event %q in %s", event, hooks[event][0].Source)
+			if suggestion := closestEvent(event); suggestion != "" {
+				warning += fmt.Sprintf(" (did you mean %q?)", suggestion)
+			}
+			warnings = append(warnings, warning)
+		}
+	}
+
+	sort.Strings(warnings)
+	return hooks, warnings
+}
+
+// closestEvent returns the known hook event nearest to a misspelled one
+func closestEvent(event string) string {
+	best, bestDistance := "", 3
+	for _, known := range HookEvents() {
+		if d := editDistance(event, known); d < bestDistance {
+			best, bestDistance = known, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
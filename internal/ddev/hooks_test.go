@@ -0,0 +1,54 @@
+package ddev
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseConfig_Hooks(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"config.yaml": `name: test-project
+hooks:
+  post-start:
+    - exec: composer install
+    - exec: mysql -e "SELECT 1"
+      service: db
+    - composer: dump-autoload
+  post-strat:
+    - exec: echo typo
+`,
+		"config.local.yaml": `hooks:
+  post-start:
+    - exec-host: open https://test-project.ddev.site
+`,
+	})
+
+	cfg, err := ParseConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	hooks := cfg.Hooks["post-start"]
+	if len(hooks) != 4 {
+		t.Fatalf("expected 4 post-start hooks, got %+v", hooks)
+	}
+
+	expected := []struct {
+		kind, container, command, source string
+	}{
+		{"exec", "web", "composer install", "config.yaml:4"},
+		{"exec", "db", `mysql -e "SELECT 1"`, "config.yaml:5"},
+		{"composer", "web", "dump-autoload", "config.yaml:7"},
+		{"exec-host", "host", "open https://test-project.ddev.site", "config.local.yaml:3"},
+	}
+	for i, want := range expected {
+		h := hooks[i]
+		if h.Kind != want.kind || h.Container != want.container || h.Command != want.command || h.Source != want.source {
+			t.Errorf("hook %d: expected %+v, got %+v", i, want, h)
+		}
+	}
+
+	if len(cfg.Warnings) != 1 || !strings.Contains(cfg.Warnings[0], `"post-strat"`) || !strings.Contains(cfg.Warnings[0], `"post-start"`) {
+		t.Errorf("expected a warning about post-strat, got %v", cfg.Warnings)
+	}
+}
@@ -0,0 +1,69 @@
+package ddev
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/dockerfile"
+	"github.com/dkd-dobberkau/ddev-explain/internal/model"
+)
+
+// DetectImageCustomizations analyzes the Dockerfiles DDEV adds to the web
+// and db images: <image>-build/pre.Dockerfile* run before DDEV's own
+// instructions, <image>-build/Dockerfile* after them
+func DetectImageCustomizations(projectPath string) []model.ImageCustomization {
+	var customizations []model.ImageCustomization
+	ddevDir := filepath.Join(projectPath, ".ddev")
+
+	for _, image := range []string{"web", "db"} {
+		var files []string
+		for _, pattern := range []string{"pre.Dockerfile*", "Dockerfile*"} {
+			matches, _ := filepath.Glob(filepath.Join(ddevDir, image+"-build", pattern))
+			sort.Strings(matches)
+			files = append(files, matches...)
+		}
+
+		for _, path := range files {
+			// DDEV ships Dockerfile.example files that are never built
+			if strings.HasSuffix(path, ".example") {
+				continue
+			}
+			instructions, err := dockerfile.ParseFile(path)
+			if err != nil {
+				continue
+			}
+			rel, _ := filepath.Rel(ddevDir, path)
+			customizations = append(customizations, imageCustomization(image, rel, dockerfile.Analyze(instructions)))
+		}
+	}
+
+	return customizations
+}
+
+func imageCustomization(image, rel string, analysis *dockerfile.Analysis) model.ImageCustomization {
+	c := model.ImageCustomization{
+		Image: image,
+		File:  rel,
+		Pre:   strings.HasPrefix(filepath.Base(rel), "pre."),
+	}
+	source := func(line int) string {
+		return fmt.Sprintf("%s:%d", rel, line)
+	}
+
+	for _, p := range analysis.Packages {
+		c.Packages = append(c.Packages, model.ImagePackage{Manager: p.Manager, Name: p.Name, Source: source(p.Line)})
+	}
+	for _, v := range analysis.Args {
+		c.Args = append(c.Args, model.EnvVar{Key: v.Key, Value: v.Value, Source: source(v.Line)})
+	}
+	for _, v := range analysis.Env {
+		c.Env = append(c.Env, model.EnvVar{Key: v.Key, Value: v.Value, Source: source(v.Line)})
+	}
+	for _, cp := range analysis.Copies {
+		c.Copies = append(c.Copies, model.ImageCopy{From: cp.Sources, To: cp.Destination, Source: source(cp.Line)})
+	}
+
+	return c
+}
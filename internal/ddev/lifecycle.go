@@ -0,0 +1,37 @@
+package ddev
+
+import (
+	"slices"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/model"
+)
+
+// LifecycleStep is one step of an action's timeline: a hook entry, or the
+// action itself when Hook is nil
+type LifecycleStep struct {
+	Event   string
+	Hook    *model.Hook
+	Skipped string // Why the hook does not run, e.g. its container is omitted
+}
+
+// Lifecycle returns the pre hooks, the action and the post hooks of a ddev
+// action, and how many of the steps are hooks
+func Lifecycle(project *model.Project, action string) ([]LifecycleStep, int) {
+	var steps []LifecycleStep
+	hooks := 0
+	for _, event := range []string{"pre-" + action, "", "post-" + action} {
+		if event == "" {
+			steps = append(steps, LifecycleStep{Event: action})
+			continue
+		}
+		for i := range project.Hooks[event] {
+			step := LifecycleStep{Event: event, Hook: &project.Hooks[event][i]}
+			if slices.Contains(project.OmitContainers, step.Hook.Container) {
+				step.Skipped = "container " + step.Hook.Container + " is omitted"
+			}
+			steps = append(steps, step)
+			hooks++
+		}
+	}
+	return steps, hooks
+}
@@ -0,0 +1,68 @@
+package ddev
+
+import "testing"
+
+func TestLifecycle(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"config.yaml": `name: test-project
+omit_containers: [db]
+hooks:
+  post-start:
+    - exec: composer install
+    - exec: mysql -e "SELECT 1"
+      service: db
+  pre-start:
+    - exec-host: ./scripts/check.sh
+  post-import-db:
+    - exec: drush cr
+`,
+		"config.local.yaml": `hooks:
+  post-start:
+    - exec-host: open https://test-project.ddev.site
+`,
+	})
+
+	cfg, err := ParseConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	steps, hooks := Lifecycle(cfg, "start")
+	if hooks != 4 {
+		t.Errorf("expected 4 hooks, got %d", hooks)
+	}
+
+	expected := []struct {
+		event, command, skipped string
+	}{
+		{"pre-start", "./scripts/check.sh", ""},
+		{"start", "", ""},
+		{"post-start", "composer install", ""},
+		{"post-start", `mysql -e "SELECT 1"`, "container db is omitted"},
+		{"post-start", "open https://test-project.ddev.site", ""},
+	}
+	if len(steps) != len(expected) {
+		t.Fatalf("expected %d steps, got %+v", len(expected), steps)
+	}
+	for i, want := range expected {
+		step := steps[i]
+		if step.Event != want.event || step.Skipped != want.skipped {
+			t.Errorf("step %d: expected %+v, got %+v", i, want, step)
+		}
+		if want.command == "" {
+			if step.Hook != nil {
+				t.Errorf("step %d: expected the action itself, got hook %+v", i, step.Hook)
+			}
+			continue
+		}
+		if step.Hook == nil || step.Hook.Command != want.command {
+			t.Errorf("step %d: expected hook %q, got %+v", i, want.command, step.Hook)
+		}
+	}
+
+	// Hooks of other actions stay out of the timeline
+	steps, hooks = Lifecycle(cfg, "stop")
+	if hooks != 0 || len(steps) != 1 || steps[0].Event != "stop" {
+		t.Errorf("expected only the stop action, got %+v", steps)
+	}
+}
@@ -0,0 +1,282 @@
+package ddev
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// configLayer is a single config file contributing to the effective config
+type configLayer struct {
+	Path     string
+	Root     *yaml.Node // top-level mapping node
+	Override bool       // override_config: true replaces instead of merging
+	Scope    string     // "" for project files, "global" or "default"
+}
+
+// loadConfigLayers reads config.yaml followed by config.*.yaml in lexical
+// order, the same sequence DDEV uses to build the effective project config
+func loadConfigLayers(ddevDir string) ([]configLayer, error) {
+	base, err := loadConfigLayer(filepath.Join(ddevDir, "config.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	layers := []configLayer{base}
+
+	var extra []string
+	for _, pattern := range []string{"config.*.yaml", "config.*.yml"} {
+		matches, _ := filepath.Glob(filepath.Join(ddevDir, pattern))
+		extra = append(extra, matches...)
+	}
+	sort.Strings(extra)
+
+	for _, path := range extra {
+		layer, err := loadConfigLayer(path)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, layer)
+	}
+
+	return layers, nil
+}
+
+func loadConfigLayer(path string) (configLayer, error) {
+	layer := configLayer{Path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return layer, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return layer, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+
+	// Empty files or files with only comments have no content
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		layer.Root = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		return layer, nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return layer, fmt.Errorf("failed to parse %s: top level is not a mapping", filepath.Base(path))
+	}
+	layer.Root = root
+
+	if v := mappingValue(root, "override_config"); v != nil && v.Kind == yaml.ScalarNode {
+		layer.Override = v.Value == "true"
+	}
+
+	return layer, nil
+}
+
+// layerValue returns the last non-empty top-level scalar set for key
+func layerValue(layers []configLayer, key string) string {
+	var value string
+	for _, layer := range layers {
+		if v := mappingValue(layer.Root, key); v != nil && v.Kind == yaml.ScalarNode && v.Value != "" {
+			value = v.Value
+		}
+	}
+	return value
+}
+
+// configMerger folds config layers into a single mapping node and keeps
+// track of which file set each key
+type configMerger struct {
+	root       *yaml.Node
+	provenance map[string][]model.Origin
+	itemFiles  map[*yaml.Node]configLayer // list item -> layer that declared it
+}
+
+func newConfigMerger() *configMerger {
+	return &configMerger{
+		root:       &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"},
+		provenance: make(map[string][]model.Origin),
+		itemFiles:  make(map[*yaml.Node]configLayer),
+	}
+}
+
+// merge applies a layer on top of the current state: scalars override,
+// lists and hooks append, and override_config replaces lists entirely
+func (m *configMerger) merge(layer configLayer) {
+	m.mergeMapping(m.root, layer.Root, layer, "")
+}
+
+func (m *configMerger) mergeMapping(dst, src *yaml.Node, layer configLayer, prefix string) {
+	replace := layer.Override
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, val := src.Content[i], src.Content[i+1]
+		if prefix == "" && key.Value == "override_config" {
+			continue
+		}
+		path := prefix + key.Value
+
+		idx := mappingIndex(dst, key.Value)
+		if val.Kind == yaml.MappingNode {
+			if idx < 0 || dst.Content[idx+1].Kind != yaml.MappingNode {
+				child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: val.Line, Column: val.Column}
+				if idx < 0 {
+					dst.Content = append(dst.Content, cloneNode(key), child)
+				} else {
+					dst.Content[idx+1] = child
+				}
+				idx = mappingIndex(dst, key.Value)
+			}
+			m.mergeMapping(dst.Content[idx+1], val, layer, path+".")
+			continue
+		}
+
+		// DDEV merges with mergo's WithOverride, which skips zero values:
+		// null, "", false, 0 and [] never change what an earlier file set
+		// unless override_config is on
+		if isZeroNode(val) && idx >= 0 && !replace {
+			continue
+		}
+		// Null and empty values set nothing where no file set the key yet
+		if isEmptyNode(val) && idx < 0 {
+			continue
+		}
+
+		mode := "set"
+		switch {
+		case idx < 0:
+			dst.Content = append(dst.Content, cloneNode(key), m.cloneTracked(val, layer))
+		case dst.Content[idx+1].Kind == yaml.SequenceNode && val.Kind == yaml.SequenceNode && !replace:
+			existing := dst.Content[idx+1]
+			existing.Content = append(existing.Content, m.cloneTracked(val, layer).Content...)
+			mode = "append"
+		default:
+			dst.Content[idx+1] = m.cloneTracked(val, layer)
+			if replace {
+				mode = "replace"
+			}
+		}
+
+		// Lines in the embedded defaults table mean nothing to the user
+		line := val.Line
+		if layer.Scope == "default" {
+			line = 0
+		}
+		m.provenance[path] = append(m.provenance[path], model.Origin{
+			File:  filepath.Base(layer.Path),
+			Line:  line,
+			Value: renderNode(val),
+			Mode:  mode,
+			Scope: layer.Scope,
+		})
+	}
+}
+
+// cloneTracked clones a value and remembers which layer declared each list
+// item, so merged lists can be attributed entry by entry
+func (m *configMerger) cloneTracked(val *yaml.Node, layer configLayer) *yaml.Node {
+	clone := cloneNode(val)
+	if clone.Kind == yaml.SequenceNode {
+		for _, item := range clone.Content {
+			m.itemFiles[item] = layer
+		}
+	}
+	return clone
+}
+
+// itemOrigin returns where a merged list item was declared
+func (m *configMerger) itemOrigin(item *yaml.Node) model.Origin {
+	layer := m.itemFiles[item]
+	origin := model.Origin{
+		File:  filepath.Base(layer.Path),
+		Line:  item.Line,
+		Value: renderNode(item),
+		Scope: layer.Scope,
+	}
+	if layer.Scope == "default" {
+		origin.Line = 0
+	}
+	return origin
+}
+
+// renderNode formats a value node as a single line for provenance output
+func renderNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value
+	case yaml.SequenceNode:
+		items := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			items = append(items, renderNode(item))
+		}
+		return strings.Join(items, ", ")
+	case yaml.MappingNode:
+		pairs := make([]string, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			pairs = append(pairs, node.Content[i].Value+": "+renderNode(node.Content[i+1]))
+		}
+		return strings.Join(pairs, ", ")
+	}
+	return ""
+}
+
+func mappingIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if idx := mappingIndex(node, key); idx >= 0 {
+		return node.Content[idx+1]
+	}
+	return nil
+}
+
+func isEmptyNode(node *yaml.Node) bool {
+	if node.Kind != yaml.ScalarNode {
+		return false
+	}
+	return node.Tag == "!!null" || node.Value == ""
+}
+
+// isZeroNode reports whether a value decodes to Go's zero value: an empty
+// node, false, a numeric zero or an empty list
+func isZeroNode(node *yaml.Node) bool {
+	if node.Kind == yaml.SequenceNode {
+		return len(node.Content) == 0
+	}
+	if isEmptyNode(node) || node.Kind != yaml.ScalarNode {
+		return isEmptyNode(node)
+	}
+	switch node.ShortTag() {
+	case "!!bool":
+		var b bool
+		return node.Decode(&b) == nil && !b
+	case "!!int", "!!float":
+		var f float64
+		return node.Decode(&f) == nil && f == 0
+	}
+	return false
+}
+
+func cloneNode(node *yaml.Node) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	clone := *node
+	if len(node.Content) > 0 {
+		clone.Content = make([]*yaml.Node, len(node.Content))
+		for i, child := range node.Content {
+			clone.Content[i] = cloneNode(child)
+		}
+	}
+	return &clone
+}
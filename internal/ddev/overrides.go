@@ -0,0 +1,109 @@
+package ddev
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/model"
+)
+
+// overrideLocations are the config directories DDEV mounts into the web and
+// db containers, with the files it picks up from each
+var overrideLocations = []struct {
+	Kind    string
+	Pattern string
+	Parse   bool // Extract key/value settings
+}{
+	{"php", "php/*.ini", true},
+	{"nginx_full", "nginx_full/*.conf", false},
+	{"nginx", "nginx/*.conf", false},
+	{"apache", "apache/*.conf", false},
+	{"mysql", "mysql/*.cnf", true},
+	{"postgres", "postgres/*.conf", true},
+}
+
+// DetectOverrides finds PHP, webserver and database config overrides in .ddev
+func DetectOverrides(projectPath string) []model.Override {
+	var overrides []model.Override
+	ddevDir := filepath.Join(projectPath, ".ddev")
+
+	for _, loc := range overrideLocations {
+		matches, _ := filepath.Glob(filepath.Join(ddevDir, loc.Pattern))
+		sort.Strings(matches)
+
+		for _, path := range matches {
+			rel, _ := filepath.Rel(ddevDir, path)
+			override := model.Override{
+				Kind:      loc.Kind,
+				File:      rel,
+				Generated: hasGeneratedMarker(path),
+			}
+			if loc.Parse {
+				override.Settings = parseSettings(path)
+			}
+			overrides = append(overrides, override)
+		}
+	}
+
+	return overrides
+}
+
+// parseSettings reads key/value pairs from ini, my.cnf and postgresql.conf
+// style files. Bare keys (skip-name-resolve) get an empty value.
+func parseSettings(path string) []model.Setting {
+	var settings []model.Setting
+
+	file, err := os.Open(path)
+	if err != nil {
+		return settings
+	}
+	defer file.Close()
+
+	section := ""
+	lineNo := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			// postgresql.conf allows "key value"
+			key, value, _ = strings.Cut(line, " ")
+		}
+		settings = append(settings, model.Setting{
+			Section: section,
+			Key:     strings.TrimSpace(key),
+			Value:   settingValue(value),
+			Line:    lineNo,
+		})
+	}
+
+	return settings
+}
+
+// settingValue drops inline comments and surrounding quotes
+func settingValue(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			return value[1 : end+1]
+		}
+	}
+	for _, marker := range []string{" #", " ;", "\t#", "\t;"} {
+		if idx := strings.Index(value, marker); idx >= 0 {
+			value = value[:idx]
+		}
+	}
+	return strings.TrimSpace(value)
+}
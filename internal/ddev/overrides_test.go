@@ -0,0 +1,58 @@
+package ddev
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectOverrides(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{"config.yaml": "name: test-project\n"})
+	files := map[string]string{
+		"php/memory.ini":             "[PHP]\nmemory_limit = 512M\nupload_max_filesize = \"64M\" ; uploads\n",
+		"nginx_full/nginx-site.conf": "#ddev-generated\nserver {}\n",
+		"apache/apache-site.conf":    "<VirtualHost *:80>\n</VirtualHost>\n",
+		"mysql/strict.cnf":           "[mysqld]\nsql_mode = STRICT_TRANS_TABLES\nskip-name-resolve\n",
+		"postgres/tuning.conf":       "# tuning\nshared_buffers = 256MB  # more memory\nwork_mem 16MB\n",
+		"php/README.txt":             "not picked up\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, ".ddev", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	overrides := DetectOverrides(tmpDir)
+	if len(overrides) != 5 {
+		t.Fatalf("expected 5 overrides, got %+v", overrides)
+	}
+
+	php := overrides[0]
+	if php.Kind != "php" || len(php.Settings) != 2 {
+		t.Fatalf("unexpected php override: %+v", php)
+	}
+	if php.Settings[1].Key != "upload_max_filesize" || php.Settings[1].Value != "64M" || php.Settings[1].Section != "PHP" {
+		t.Errorf("unexpected php setting: %+v", php.Settings[1])
+	}
+
+	if !overrides[1].Generated || overrides[1].Kind != "nginx_full" {
+		t.Errorf("expected nginx_full config to be generated, got %+v", overrides[1])
+	}
+	if overrides[2].Generated || overrides[2].Kind != "apache" {
+		t.Errorf("expected apache config to be customized, got %+v", overrides[2])
+	}
+
+	mysql := overrides[3]
+	if len(mysql.Settings) != 2 || mysql.Settings[1].Key != "skip-name-resolve" || mysql.Settings[1].Value != "" {
+		t.Errorf("unexpected mysql settings: %+v", mysql.Settings)
+	}
+
+	postgres := overrides[4]
+	if len(postgres.Settings) != 2 || postgres.Settings[0].Value != "256MB" || postgres.Settings[1].Value != "16MB" {
+		t.Errorf("unexpected postgres settings: %+v", postgres.Settings)
+	}
+}
@@ -0,0 +1,149 @@
+package ddev
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// providerCommands are the tasks a provider file can define, in the order
+// ddev pull and ddev push run them
+var providerCommands = []string{
+	"auth_command",
+	"db_pull_command",
+	"files_pull_command",
+	"db_push_command",
+	"files_push_command",
+}
+
+// providerVarPattern matches $VAR and ${VAR} references to upper-case variables
+var providerVarPattern = regexp.MustCompile(`\$\{?([A-Z_][A-Z0-9_]*)`)
+
+// providerTask represents a *_command entry in a provider file
+type providerTask struct {
+	Command string `yaml:"command"`
+	Service string `yaml:"service"`
+}
+
+// DetectProviders reads the hosting provider integrations in
+// .ddev/providers/*.yaml. env holds the variables set per container;
+// provider commands run in web unless they name another service or host.
+func DetectProviders(projectPath string, env map[string][]model.EnvVar) []model.Provider {
+	var providers []model.Provider
+
+	matches, _ := filepath.Glob(filepath.Join(projectPath, ".ddev", "providers", "*.yaml"))
+	sort.Strings(matches)
+
+	for _, path := range matches {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var raw map[string]yaml.Node
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			continue
+		}
+
+		provider := model.Provider{
+			Name:      strings.TrimSuffix(filepath.Base(path), ".yaml"),
+			File:      filepath.Join("providers", filepath.Base(path)),
+			Generated: hasGeneratedMarker(path),
+		}
+
+		var vars map[string]string
+		if node, ok := raw["environment_variables"]; ok {
+			node.Decode(&vars)
+		}
+		for _, key := range sortedKeys(vars) {
+			provider.Variables = append(provider.Variables, model.EnvVar{Key: key, Value: vars[key], Source: provider.File})
+		}
+
+		// Variable -> containers whose commands use it
+		required := make(map[string]map[string]bool)
+		for _, name := range providerCommands {
+			node, ok := raw[name]
+			if !ok {
+				continue
+			}
+			var task providerTask
+			if err := node.Decode(&task); err != nil || strings.TrimSpace(task.Command) == "" {
+				continue
+			}
+			provider.Commands = append(provider.Commands, name)
+
+			container := task.Service
+			if container == "" {
+				container = "web"
+			}
+			for _, m := range providerVarPattern.FindAllStringSubmatch(task.Command, -1) {
+				if providedByDDEV(m[1]) {
+					continue
+				}
+				if required[m[1]] == nil {
+					required[m[1]] = make(map[string]bool)
+				}
+				required[m[1]][container] = true
+			}
+		}
+
+		for _, key := range sortedKeys(required) {
+			if _, ok := vars[key]; ok {
+				continue
+			}
+			provider.RequiredEnv = append(provider.RequiredEnv, key)
+			for _, container := range sortedKeys(required[key]) {
+				if !envAvailable(env, container, key) {
+					provider.MissingEnv = append(provider.MissingEnv, key)
+					break
+				}
+			}
+		}
+
+		providers = append(providers, provider)
+	}
+
+	return providers
+}
+
+// envAvailable reports whether a variable is set where a provider command
+// runs: in the shell of ddev-explain for service: host, else in the
+// container's environment
+func envAvailable(env map[string][]model.EnvVar, container, key string) bool {
+	if container == "host" {
+		_, ok := os.LookupEnv(key)
+		return ok
+	}
+	for _, v := range env[container] {
+		if v.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// providedByDDEV reports whether DDEV or the shell sets a variable itself
+func providedByDDEV(name string) bool {
+	if strings.HasPrefix(name, "DDEV_") {
+		return true
+	}
+	switch name {
+	case "HOME", "PATH", "PWD", "USER", "HOSTNAME", "IS_DDEV_PROJECT":
+		return true
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
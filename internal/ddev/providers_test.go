@@ -0,0 +1,132 @@
+package ddev
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseConfig_Providers(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"config.yaml": `name: test-project
+web_environment:
+  - PLATFORMSH_CLI_TOKEN=secret
+`,
+	})
+	files := map[string]string{
+		"platform.yaml": `environment_variables:
+  project_id: abc123
+auth_command:
+  command: platform auth:api-token-login --token "${PLATFORMSH_CLI_TOKEN}"
+db_pull_command:
+  command: platform db:dump --project="${project_id}" --directory=$DDEV_APPROOT
+files_pull_command:
+  command: platform mount:download --yes
+`,
+		"rsync.yaml": `db_pull_command:
+  command: scp "$REMOTE_HOST:db.sql.gz" .
+`,
+		"acquia.yaml.example": "auth_command:\n  command: true\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, ".ddev", "providers", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create providers dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	cfg, err := ParseConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	if len(cfg.Providers) != 2 {
+		t.Fatalf("expected 2 providers, got %+v", cfg.Providers)
+	}
+
+	platform := cfg.Providers[0]
+	if platform.Name != "platform" || len(platform.Commands) != 3 {
+		t.Errorf("unexpected platform provider: %+v", platform)
+	}
+	if !platform.HasCommand("files_pull_command") || platform.HasCommand("db_push_command") {
+		t.Errorf("unexpected platform commands: %v", platform.Commands)
+	}
+	if len(platform.Variables) != 1 || platform.Variables[0].Value != "abc123" {
+		t.Errorf("expected project_id variable, got %+v", platform.Variables)
+	}
+	if len(platform.RequiredEnv) != 1 || platform.RequiredEnv[0] != "PLATFORMSH_CLI_TOKEN" {
+		t.Errorf("expected PLATFORMSH_CLI_TOKEN to be required, got %v", platform.RequiredEnv)
+	}
+	if len(platform.MissingEnv) != 0 {
+		t.Errorf("expected web_environment to provide the token, got missing %v", platform.MissingEnv)
+	}
+
+	rsync := cfg.Providers[1]
+	if len(rsync.MissingEnv) != 1 || rsync.MissingEnv[0] != "REMOTE_HOST" {
+		t.Errorf("expected REMOTE_HOST to be missing, got %v", rsync.MissingEnv)
+	}
+}
+
+func TestParseConfig_ProvidersGeneratedAndScoped(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"config.yaml": `name: test-project
+web_environment:
+  - SSH_TARGET=deploy@example.com
+`,
+	})
+	t.Setenv("HOST_TOKEN", "set-on-host")
+	files := map[string]string{
+		"acquia.yaml": `#ddev-generated
+auth_command:
+  command: acli auth:login -k "${ACQUIA_API_KEY}"
+db_pull_command:
+  command: acli pull:db ${ACQUIA_ENVIRONMENT_ID}
+`,
+		"a-first.yaml": `environment_variables:
+  REMOTE_HOST: first.example.com
+db_pull_command:
+  command: scp "$REMOTE_HOST:db.sql.gz" .
+`,
+		"b-second.yaml": `db_pull_command:
+  command: scp "$REMOTE_HOST:db.sql.gz" .
+files_pull_command:
+  service: host
+  command: rsync -a "$SSH_TARGET:files/" . --token "$HOST_TOKEN"
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, ".ddev", "providers", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create providers dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	cfg, err := ParseConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if len(cfg.Providers) != 3 {
+		t.Fatalf("expected 3 providers, got %+v", cfg.Providers)
+	}
+
+	first, acquia, second := cfg.Providers[0], cfg.Providers[1], cfg.Providers[2]
+	if first.Generated || !acquia.Generated || second.Generated {
+		t.Errorf("expected only acquia to be generated, got %v %v %v", first.Generated, acquia.Generated, second.Generated)
+	}
+	if len(first.MissingEnv) != 0 {
+		t.Errorf("expected first to set REMOTE_HOST itself, got missing %v", first.MissingEnv)
+	}
+
+	// REMOTE_HOST from another provider does not count, and web_environment
+	// does not reach a service: host command
+	want := []string{"REMOTE_HOST", "SSH_TARGET"}
+	if len(second.MissingEnv) != len(want) || second.MissingEnv[0] != want[0] || second.MissingEnv[1] != want[1] {
+		t.Errorf("expected %v to be missing, got %v", want, second.MissingEnv)
+	}
+}
@@ -1,64 +1,46 @@
 package ddev
 
 import (
-	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/dkd-dobberkau/ddev-explain/internal/compose"
 	"github.com/dkd-dobberkau/ddev-explain/internal/model"
-	"gopkg.in/yaml.v3"
 )
 
-// DetectServices finds additional services from docker-compose files
-func DetectServices(projectPath string) ([]model.Service, error) {
+// DetectServices finds additional services from docker-compose files.
+// Variables in the files are expanded from env. Files that cannot be
+// loaded are reported as warnings.
+func DetectServices(projectPath string, env map[string]string) ([]model.Service, []string, error) {
 	var services []model.Service
 
-	ddevDir := filepath.Join(projectPath, ".ddev")
-	entries, err := os.ReadDir(ddevDir)
+	files, loadErrs, err := compose.LoadAll(filepath.Join(projectPath, ".ddev"), env)
 	if err != nil {
-		return services, err
+		return services, nil, err
 	}
 
-	for _, entry := range entries {
-		if strings.HasPrefix(entry.Name(), "docker-compose.") &&
-			strings.HasSuffix(entry.Name(), ".yaml") &&
-			entry.Name() != "docker-compose.yaml" {
+	for _, file := range files {
+		services = append(services, servicesFromCompose(file)...)
+	}
 
-			filePath := filepath.Join(ddevDir, entry.Name())
-			svcList, err := parseServicesFromCompose(filePath)
-			if err == nil {
-				services = append(services, svcList...)
-			}
-		}
+	var warnings []string
+	for _, err := range loadErrs {
+		warnings = append(warnings, compose.LoadWarning(err))
 	}
 
-	return services, nil
+	return services, warnings, nil
 }
 
-func parseServicesFromCompose(filePath string) ([]model.Service, error) {
+func servicesFromCompose(file *compose.File) []model.Service {
 	var services []model.Service
+	generated := hasGeneratedMarker(file.Path)
 
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, err
-	}
-
-	var dc struct {
-		Services map[string]struct {
-			Image string   `yaml:"image"`
-			Ports []string `yaml:"ports"`
-		} `yaml:"services"`
-	}
-
-	if err := yaml.Unmarshal(data, &dc); err != nil {
-		return nil, err
-	}
-
-	for name, svc := range dc.Services {
+	for _, name := range file.ServiceNames() {
 		// Skip standard DDEV services
 		if name == "web" || name == "db" || name == "dba" {
 			continue
 		}
+		svc := file.Services[name]
 
 		serviceType := "custom"
 		if strings.Contains(svc.Image, "solr") {
@@ -71,12 +53,64 @@ func parseServicesFromCompose(filePath string) ([]model.Service, error) {
 			serviceType = "mail"
 		}
 
+		var ports []string
+		for _, p := range svc.Ports {
+			ports = append(ports, p.String())
+		}
+
+		services = append(services, model.Service{
+			Name:      name,
+			Type:      serviceType,
+			Ports:     ports,
+			File:      filepath.Base(file.Path),
+			Generated: generated,
+		})
+	}
+
+	return services
+}
+
+// DetectWebDaemons turns web_extra_daemons and web_extra_exposed_ports into
+// services. A daemon and an exposed port with the same name are combined;
+// a port without a daemon becomes a web-port service.
+func DetectWebDaemons(daemons []model.Daemon, ports []model.ExposedPort) []model.Service {
+	var services []model.Service
+
+	portsByName := make(map[string]model.ExposedPort)
+	for _, p := range ports {
+		portsByName[p.Name] = p
+	}
+
+	linked := make(map[string]bool)
+	for _, d := range daemons {
+		svc := model.Service{
+			Name:    d.Name,
+			Type:    "web-daemon",
+			Command: d.Command,
+		}
+		if p, ok := portsByName[d.Name]; ok {
+			svc.ContainerPort = p.ContainerPort
+			svc.HTTPPort = p.HTTPPort
+			svc.HTTPSPort = p.HTTPSPort
+			linked[p.Name] = true
+		}
+		services = append(services, svc)
+	}
+
+	// Exposed ports without a daemon are served by something started by
+	// hand, so they are not reported as daemons
+	for _, p := range ports {
+		if linked[p.Name] {
+			continue
+		}
 		services = append(services, model.Service{
-			Name:  name,
-			Type:  serviceType,
-			Ports: svc.Ports,
+			Name:          p.Name,
+			Type:          "web-port",
+			ContainerPort: p.ContainerPort,
+			HTTPPort:      p.HTTPPort,
+			HTTPSPort:     p.HTTPSPort,
 		})
 	}
 
-	return services, nil
+	return services
 }
@@ -0,0 +1,62 @@
+package ddev
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/model"
+)
+
+func TestDetectWebDaemons(t *testing.T) {
+	daemons := []model.Daemon{
+		{Name: "vite", Command: "npm run dev"},
+		{Name: "queue", Command: "php artisan queue:work"},
+	}
+	ports := []model.ExposedPort{
+		{Name: "vite", ContainerPort: 5173, HTTPPort: 5172, HTTPSPort: 5173},
+		{Name: "storybook", ContainerPort: 6006, HTTPSPort: 6007},
+	}
+
+	services := DetectWebDaemons(daemons, ports)
+	if len(services) != 3 {
+		t.Fatalf("expected 3 services, got %d", len(services))
+	}
+
+	vite := services[0]
+	if vite.Type != "web-daemon" || vite.Command != "npm run dev" {
+		t.Errorf("unexpected vite service: %+v", vite)
+	}
+	if vite.ContainerPort != 5173 || vite.HTTPPort != 5172 || vite.HTTPSPort != 5173 {
+		t.Errorf("expected vite to be linked to its exposed port, got %+v", vite)
+	}
+
+	if services[1].Name != "queue" || services[1].ContainerPort != 0 {
+		t.Errorf("expected queue daemon without ports, got %+v", services[1])
+	}
+
+	if services[2].Name != "storybook" || services[2].Type != "web-port" || services[2].Command != "" || services[2].HTTPSPort != 6007 {
+		t.Errorf("expected storybook port without daemon, got %+v", services[2])
+	}
+}
+
+func TestDetectServices_BrokenComposeFiles(t *testing.T) {
+	tmpDir := writeConfigFiles(t, map[string]string{
+		"docker-compose.redis.yaml":  "services:\n  redis:\n    image: redis:7\n",
+		"docker-compose.broken.yaml": "services:\n  solr: [\n",
+		"docker-compose.search.yaml": "services:\n  search:\n    image: ${SEARCH_IMAGE:?SEARCH_IMAGE must be set}\n",
+	})
+
+	services, warnings, err := DetectServices(tmpDir, nil)
+	if err != nil {
+		t.Fatalf("DetectServices failed: %v", err)
+	}
+	if len(services) != 1 || services[0].Name != "redis" {
+		t.Errorf("expected only the redis service, got %+v", services)
+	}
+	if len(warnings) != 2 {
+		t.Fatalf("expected a warning per broken file, got %v", warnings)
+	}
+	if !strings.Contains(warnings[0], "docker-compose.broken.yaml") || !strings.Contains(warnings[1], "SEARCH_IMAGE must be set") {
+		t.Errorf("expected warnings to name the file and the error, got %v", warnings)
+	}
+}
@@ -0,0 +1,76 @@
+package ddev
+
+import "fmt"
+
+const (
+	defaultProjectTLD      = "ddev.site"
+	defaultRouterHTTPPort  = "80"
+	defaultRouterHTTPSPort = "443"
+)
+
+// buildURLs returns the URLs the DDEV router serves for a project: HTTPS
+// for every hostname first, then HTTP, with the primary hostname leading.
+// Wildcard hostnames are kept as patterns, e.g. https://*.example.ddev.site
+func buildURLs(cfg DDEVConfig) []string {
+	hostnames := projectHostnames(cfg)
+	if len(hostnames) == 0 {
+		return nil
+	}
+
+	httpsPort := orDefault(cfg.RouterHTTPSPort, defaultRouterHTTPSPort)
+	httpPort := orDefault(cfg.RouterHTTPPort, defaultRouterHTTPPort)
+
+	var urls []string
+	for _, host := range hostnames {
+		urls = append(urls, formatURL("https", host, httpsPort, defaultRouterHTTPSPort))
+	}
+	for _, host := range hostnames {
+		urls = append(urls, formatURL("http", host, httpPort, defaultRouterHTTPPort))
+	}
+
+	return urls
+}
+
+// projectHostnames mirrors DDEV's hostname list: the project name and each
+// additional_hostname under project_tld, followed by additional_fqdns as-is
+func projectHostnames(cfg DDEVConfig) []string {
+	tld := orDefault(cfg.ProjectTLD, defaultProjectTLD)
+
+	var hostnames []string
+	seen := make(map[string]bool)
+	add := func(host string) {
+		if host == "" || seen[host] {
+			return
+		}
+		seen[host] = true
+		hostnames = append(hostnames, host)
+	}
+
+	if cfg.Name != "" {
+		add(cfg.Name + "." + tld)
+	}
+	for _, h := range cfg.AdditionalHostnames {
+		if h != "" {
+			add(h + "." + tld)
+		}
+	}
+	for _, fqdn := range cfg.AdditionalFQDNs {
+		add(fqdn)
+	}
+
+	return hostnames
+}
+
+func formatURL(scheme, host, port, defaultPort string) string {
+	if port == defaultPort {
+		return fmt.Sprintf("%s://%s", scheme, host)
+	}
+	return fmt.Sprintf("%s://%s:%s", scheme, host, port)
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
@@ -1,10 +1,14 @@
 package detector
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
+	"github.com/dkd-dobberkau/ddev-explain/internal/compose"
 	"github.com/dkd-dobberkau/ddev-explain/internal/composer"
 	"github.com/dkd-dobberkau/ddev-explain/internal/model"
 )
@@ -25,10 +29,21 @@ var ignoredFiles = map[string]bool{
 
 // DetectDevPaths finds all development directories in a project
 func DetectDevPaths(projectPath string) ([]model.DevPath, error) {
+	return DetectProjectDevPaths(&model.Project{Path: projectPath})
+}
+
+// DetectProjectDevPaths is like DetectDevPaths but uses the parsed project
+// config, e.g. to expand ${DDEV_APPROOT} in compose volumes and to find
+// composer.json below composer_root. Compose files whose mounts could not
+// be read and a composer.json or composer.lock that cannot be parsed are
+// added to project.Warnings.
+func DetectProjectDevPaths(project *model.Project) ([]model.DevPath, error) {
 	var devPaths []model.DevPath
+	projectPath := project.Path
+	composerRoot := composer.Root(projectPath, project.ComposerRoot)
 
 	// 1. Composer path repositories
-	composerPaths, err := detectComposerPaths(projectPath)
+	composerPaths, err := detectComposerPaths(projectPath, composerRoot)
 	if err == nil {
 		devPaths = append(devPaths, composerPaths...)
 	}
@@ -38,75 +53,192 @@ func DetectDevPaths(projectPath string) ([]model.DevPath, error) {
 	devPaths = append(devPaths, conventionPaths...)
 
 	// 3. Symlinks in vendor
-	symlinkPaths, err := detectSymlinks(projectPath)
+	symlinkPaths, err := detectSymlinks(projectPath, composer.VendorDir(composerRoot))
 	if err == nil {
 		devPaths = append(devPaths, symlinkPaths...)
 	}
 
 	// 4. Docker mounts
-	mountPaths, err := detectMounts(projectPath)
+	mountPaths, loadErrs, err := detectMounts(projectPath, compose.ProjectEnv(project))
 	if err == nil {
 		devPaths = append(devPaths, mountPaths...)
 	}
+	// Parsing the config already reports compose files that failed to load
+	for _, loadErr := range loadErrs {
+		addWarning(project, compose.LoadWarning(loadErr))
+	}
 
 	// Deduplicate
 	devPaths = deduplicatePaths(devPaths)
 
+	// Relate the packages to the root composer.json and composer.lock. A
+	// composer.json that cannot be parsed also hides its repositories and
+	// vendor-dir, so it is reported.
+	root, err := composer.ParseComposerJSON(composerRoot)
+	if err != nil && !os.IsNotExist(err) {
+		addWarning(project, composerParseError(projectPath, composerRoot, "composer.json", err).Error())
+	}
+	lock, err := composer.ParseLock(composerRoot)
+	if err != nil {
+		addWarning(project, composerParseError(projectPath, composerRoot, "composer.lock", err).Error())
+	}
+	for i := range devPaths {
+		annotatePackages(&devPaths[i], root, lock)
+	}
+
 	return devPaths, nil
 }
 
-func detectComposerPaths(projectPath string) ([]model.DevPath, error) {
+// addWarning adds a warning to the project unless it is already there
+func addWarning(project *model.Project, warning string) {
+	if !slices.Contains(project.Warnings, warning) {
+		project.Warnings = append(project.Warnings, warning)
+	}
+}
+
+// composerParseError names the composer.json or composer.lock, relative to
+// the project, that could not be parsed
+func composerParseError(projectPath, composerRoot, file string, err error) error {
+	rel, relErr := filepath.Rel(projectPath, filepath.Join(composerRoot, file))
+	if relErr != nil {
+		rel = file
+	}
+	return fmt.Errorf("failed to parse %s: %w", rel, err)
+}
+
+// detectComposerPaths finds local repositories in the composer.json in
+// composerRoot; relative urls are resolved against composerRoot
+func detectComposerPaths(projectPath, composerRoot string) ([]model.DevPath, error) {
 	var devPaths []model.DevPath
 
-	paths, err := composer.ParsePathRepositories(projectPath)
+	repos, err := composer.ParseRepositories(composerRoot)
 	if err != nil {
 		return nil, err
 	}
-
-	for _, p := range paths {
-		// Skip container-only paths (not accessible on host)
-		if strings.HasPrefix(p, "/var/www/") {
-			continue
+	source, _ := filepath.Rel(projectPath, filepath.Join(composerRoot, "composer.json"))
+
+	for _, repo := range repos {
+		switch {
+		case repo.Type == "path":
+			devPaths = append(devPaths, pathRepositoryDevPaths(composerRoot, source, repo)...)
+		case (repo.Type == "vcs" || repo.Type == "git") && repo.IsLocal():
+			dir := resolveRepositoryPath(composerRoot, repo.LocalPath())
+			dp := model.DevPath{
+				Path:       dir,
+				Type:       "composer-vcs",
+				Source:     source,
+				UpdateHint: "edits must be committed, then composer update",
+			}
+			if pkg, ok := readPackage(dir); ok {
+				dp.Packages = []model.Package{pkg}
+			}
+			devPaths = append(devPaths, dp)
+		case repo.Type == "artifact" && repo.IsLocal():
+			dir := resolveRepositoryPath(composerRoot, repo.LocalPath())
+			devPaths = append(devPaths, model.DevPath{
+				Path:       dir,
+				Type:       "composer-artifact",
+				Source:     source,
+				Packages:   findArtifacts(dir),
+				UpdateHint: "edits require rebuilding the zip, then composer update",
+			})
 		}
+	}
 
-		absPath := p
-		if !filepath.IsAbs(p) {
-			absPath = filepath.Join(projectPath, p)
-		}
+	return devPaths, nil
+}
+
+// pathRepositoryDevPaths returns the directories a path repository url
+// matches, which may contain a glob
+func pathRepositoryDevPaths(composerRoot, source string, repo composer.Repository) []model.DevPath {
+	var devPaths []model.DevPath
+	p := repo.URL
+
+	// Skip container-only paths (not accessible on host)
+	if strings.HasPrefix(p, "/var/www/") {
+		return devPaths
+	}
+
+	absPath := resolveRepositoryPath(composerRoot, p)
+
+	// Skip ignored files
+	if ignoredFiles[filepath.Base(absPath)] {
+		return devPaths
+	}
+
+	matches := []string{absPath}
+	// Handle glob patterns
+	if strings.Contains(absPath, "*") {
+		matches, _ = filepath.Glob(absPath)
+	}
 
-		// Skip ignored files
-		if ignoredFiles[filepath.Base(absPath)] {
+	for _, match := range matches {
+		// Skip ignored files in glob matches
+		if ignoredFiles[filepath.Base(match)] {
 			continue
 		}
+		dp := model.DevPath{
+			Path:     match,
+			Type:     "composer-path",
+			Source:   source,
+			Packages: findPackagesInDir(match),
+		}
+		if repo.Copied() {
+			dp.UpdateHint = "copied, not symlinked: edits require composer update"
+		}
+		// options.versions sets the version composer reports for a package
+		for i := range dp.Packages {
+			if v, ok := repo.Options.Versions[dp.Packages[i].Name]; ok && dp.Packages[i].Version == "" {
+				dp.Packages[i].Version = v
+			}
+		}
+		devPaths = append(devPaths, dp)
+	}
 
-		// Handle glob patterns
-		if strings.Contains(absPath, "*") {
-			matches, _ := filepath.Glob(absPath)
-			for _, match := range matches {
-				// Skip ignored files in glob matches
-				if ignoredFiles[filepath.Base(match)] {
-					continue
-				}
-				packages := findPackagesInDir(match)
-				devPaths = append(devPaths, model.DevPath{
-					Path:     match,
-					Type:     "composer-path",
-					Source:   "composer.json",
-					Packages: packages,
-				})
+	return devPaths
+}
+
+func resolveRepositoryPath(composerRoot, p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(composerRoot, p)
+}
+
+// findArtifacts lists the packages zipped in an artifact repository
+func findArtifacts(dir string) []model.Package {
+	var packages []model.Package
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.zip"))
+	for _, zipPath := range matches {
+		pkg := model.Package{Name: filepath.Base(zipPath), Dir: filepath.Base(zipPath)}
+		if c, err := composer.ReadArtifact(zipPath); err == nil {
+			if c.Name != "" {
+				pkg.Name = c.Name
 			}
-		} else {
-			packages := findPackagesInDir(absPath)
-			devPaths = append(devPaths, model.DevPath{
-				Path:     absPath,
-				Type:     "composer-path",
-				Source:   "composer.json",
-				Packages: packages,
-			})
+			pkg.Type = c.Type
+			pkg.Version = c.Version
+			pkg.Description = c.Description
 		}
+		packages = append(packages, pkg)
 	}
 
-	return devPaths, nil
+	return packages
+}
+
+// Warnings reports path repository packages composer did not install from
+// their path, so local changes to them have no effect
+func Warnings(devPaths []model.DevPath) []string {
+	var warnings []string
+	for _, dp := range devPaths {
+		for _, pkg := range dp.Packages {
+			if pkg.Lock != nil && pkg.Lock.Installed && !pkg.Lock.FromPath() {
+				warnings = append(warnings, fmt.Sprintf("%s is in path repository %s but was installed from %s (%s); local changes have no effect",
+					pkg.Name, dp.Path, pkg.Lock.InstalledFrom, pkg.Lock.Version))
+			}
+		}
+	}
+	return warnings
 }
 
 func detectConventionalPaths(projectPath string) []model.DevPath {
@@ -130,9 +262,9 @@ func detectConventionalPaths(projectPath string) []model.DevPath {
 	return devPaths
 }
 
-func detectSymlinks(projectPath string) ([]model.DevPath, error) {
+// detectSymlinks finds packages in vendorPath that link outside of it
+func detectSymlinks(projectPath, vendorPath string) ([]model.DevPath, error) {
 	var devPaths []model.DevPath
-	vendorPath := filepath.Join(projectPath, "vendor")
 
 	if _, err := os.Stat(vendorPath); os.IsNotExist(err) {
 		return devPaths, nil
@@ -176,8 +308,8 @@ func detectSymlinks(projectPath string) ([]model.DevPath, error) {
 	return devPaths, nil
 }
 
-func findPackagesInDir(dir string) []string {
-	var packages []string
+func findPackagesInDir(dir string) []model.Package {
+	var packages []model.Package
 
 	entries, err := os.ReadDir(dir)
 	if err != nil {
@@ -186,25 +318,84 @@ func findPackagesInDir(dir string) []string {
 
 	for _, entry := range entries {
 		if entry.IsDir() {
-			composerPath := filepath.Join(dir, entry.Name(), "composer.json")
-			if _, err := os.Stat(composerPath); err == nil {
-				packages = append(packages, entry.Name())
+			if pkg, ok := readPackage(filepath.Join(dir, entry.Name())); ok {
+				packages = append(packages, pkg)
 			}
 		}
 	}
 
 	// Also check if dir itself is a package
-	if _, err := os.Stat(filepath.Join(dir, "composer.json")); err == nil && len(packages) == 0 {
-		packages = append(packages, filepath.Base(dir))
+	if len(packages) == 0 {
+		if pkg, ok := readPackage(dir); ok {
+			packages = append(packages, pkg)
+		}
 	}
 
 	return packages
 }
 
+// readPackage describes the Composer package in dir, if it has a composer.json
+func readPackage(dir string) (model.Package, bool) {
+	if _, err := os.Stat(filepath.Join(dir, "composer.json")); err != nil {
+		return model.Package{}, false
+	}
+
+	pkg := model.Package{Name: filepath.Base(dir), Dir: filepath.Base(dir)}
+	c, err := composer.ParseComposerJSON(dir)
+	if err != nil {
+		return pkg, true
+	}
+
+	if c.Name != "" {
+		pkg.Name = c.Name
+	}
+	pkg.Type = c.Type
+	pkg.Version = c.Version
+	pkg.Description = c.Description
+
+	var aliases []string
+	for _, branch := range sortedKeys(c.Extra.BranchAlias) {
+		aliases = append(aliases, branch+" as "+c.Extra.BranchAlias[branch])
+	}
+	pkg.BranchAlias = strings.Join(aliases, ", ")
+
+	return pkg, true
+}
+
+// annotatePackages adds the root requirement and, for path repositories,
+// the composer.lock entry to each package of a dev path
+func annotatePackages(dp *model.DevPath, root *composer.ComposerJSON, lock *composer.Lock) {
+	for i := range dp.Packages {
+		pkg := &dp.Packages[i]
+		if root != nil {
+			pkg.Constraint, pkg.Dev = root.Constraint(pkg.Name)
+		}
+		if lock == nil || dp.Type != "composer-path" {
+			continue
+		}
+
+		pkg.Lock = &model.PackageLock{}
+		if locked, ok := lock.Find(pkg.Name); ok {
+			pkg.Lock.Installed = true
+			pkg.Lock.Version = locked.Version
+			pkg.Lock.InstalledFrom = locked.InstalledFrom()
+		}
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // typePriority returns priority for deduplication (lower = higher priority)
 func typePriority(t string) int {
 	switch t {
-	case "composer-path":
+	case "composer-path", "composer-vcs", "composer-artifact":
 		return 1
 	case "symlink":
 		return 2
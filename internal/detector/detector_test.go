@@ -3,7 +3,10 @@ package detector
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/model"
 )
 
 func TestDetectDevPaths(t *testing.T) {
@@ -68,8 +71,11 @@ func TestDetectDevPaths_ConventionalDirs(t *testing.T) {
 	for _, p := range paths {
 		if p.Type == "convention" && len(p.Packages) > 0 {
 			found = true
-			if p.Packages[0] != "test-package" {
-				t.Errorf("expected package 'test-package', got '%s'", p.Packages[0])
+			if p.Packages[0].Dir != "test-package" {
+				t.Errorf("expected package 'test-package', got '%s'", p.Packages[0].Dir)
+			}
+			if p.Packages[0].Name != "test/package" {
+				t.Errorf("expected package name 'test/package', got '%s'", p.Packages[0].Name)
 			}
 			break
 		}
@@ -166,3 +172,377 @@ func TestDetectDevPaths_Deduplication(t *testing.T) {
 		t.Errorf("expected deduplication, but found %d entries for my-ext", count)
 	}
 }
+
+func TestDetectDevPaths_LongSyntaxMounts(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "project")
+	ddevDir := filepath.Join(projectDir, ".ddev")
+	if err := os.MkdirAll(ddevDir, 0755); err != nil {
+		t.Fatalf("failed to create ddev dir: %v", err)
+	}
+
+	composeYAML := `services:
+  web:
+    volumes:
+      - type: bind
+        source: ../../shared
+        target: /var/www/shared
+        read_only: true
+        bind:
+          propagation: rshared
+`
+	if err := os.WriteFile(filepath.Join(ddevDir, "docker-compose.shared.yml"), []byte(composeYAML), 0644); err != nil {
+		t.Fatalf("failed to write compose file: %v", err)
+	}
+
+	paths, err := DetectDevPaths(projectDir)
+	if err != nil {
+		t.Fatalf("DetectDevPaths failed: %v", err)
+	}
+
+	if len(paths) != 1 {
+		t.Fatalf("expected 1 mount, got %d", len(paths))
+	}
+	mount := paths[0]
+	if mount.Type != "mount" || mount.Path != filepath.Join(tmpDir, "shared") {
+		t.Errorf("unexpected mount: %+v", mount)
+	}
+	if !mount.ReadOnly || mount.Propagation != "rshared" || mount.MountTarget != "/var/www/shared" {
+		t.Errorf("expected read-only rshared mount at /var/www/shared, got %+v", mount)
+	}
+}
+
+func TestDetectDevPaths_InterpolatedMount(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "project")
+	ddevDir := filepath.Join(projectDir, ".ddev")
+	if err := os.MkdirAll(ddevDir, 0755); err != nil {
+		t.Fatalf("failed to create ddev dir: %v", err)
+	}
+
+	composeYAML := `services:
+  web:
+    volumes:
+      - ${DDEV_APPROOT}/../shared:/var/www/shared
+`
+	if err := os.WriteFile(filepath.Join(ddevDir, "docker-compose.shared.yaml"), []byte(composeYAML), 0644); err != nil {
+		t.Fatalf("failed to write compose file: %v", err)
+	}
+
+	paths, err := DetectDevPaths(projectDir)
+	if err != nil {
+		t.Fatalf("DetectDevPaths failed: %v", err)
+	}
+
+	if len(paths) != 1 || paths[0].Path != filepath.Join(tmpDir, "shared") {
+		t.Errorf("expected ${DDEV_APPROOT}/../shared to resolve to %s, got %+v", filepath.Join(tmpDir, "shared"), paths)
+	}
+}
+
+func TestDetectProjectDevPaths_MountInterpolationError(t *testing.T) {
+	tmpDir := t.TempDir()
+	ddevDir := filepath.Join(tmpDir, ".ddev")
+	if err := os.MkdirAll(ddevDir, 0755); err != nil {
+		t.Fatalf("failed to create ddev dir: %v", err)
+	}
+
+	composeYAML := `services:
+  web:
+    volumes:
+      - ${SHARED_DIR:?SHARED_DIR is not set}:/var/www/shared
+`
+	if err := os.WriteFile(filepath.Join(ddevDir, "docker-compose.shared.yaml"), []byte(composeYAML), 0644); err != nil {
+		t.Fatalf("failed to write compose file: %v", err)
+	}
+
+	project := &model.Project{Path: tmpDir}
+	paths, err := DetectProjectDevPaths(project)
+	if err != nil {
+		t.Fatalf("DetectProjectDevPaths failed: %v", err)
+	}
+	if len(paths) != 0 {
+		t.Errorf("expected no mounts, got %+v", paths)
+	}
+	if len(project.Warnings) != 1 || !strings.Contains(project.Warnings[0], "SHARED_DIR is not set") {
+		t.Errorf("expected a warning about the compose file, got %v", project.Warnings)
+	}
+
+	// A warning ParseConfig already added is not repeated
+	if _, err := DetectProjectDevPaths(project); err != nil {
+		t.Fatalf("DetectProjectDevPaths failed: %v", err)
+	}
+	if len(project.Warnings) != 1 {
+		t.Errorf("expected the warning once, got %v", project.Warnings)
+	}
+}
+
+func TestDetectDevPaths_ComposerLock(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for name, pkg := range map[string]string{"foo": "acme/foo", "bar": "acme/bar"} {
+		dir := filepath.Join(tmpDir, "packages", name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create package dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "composer.json"), []byte(`{"name": "`+pkg+`"}`), 0644); err != nil {
+			t.Fatalf("failed to write composer.json: %v", err)
+		}
+	}
+
+	files := map[string]string{
+		"composer.json": `{"repositories": [{"type": "path", "url": "packages/*"}]}`,
+		"composer.lock": `{
+	"packages": [
+		{"name": "acme/foo", "version": "dev-main", "dist": {"type": "path", "url": "packages/foo"}},
+		{"name": "acme/bar", "version": "1.2.3", "dist": {"type": "zip", "url": "https://example.com/bar.zip"}}
+	],
+	"packages-dev": []
+}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	paths, err := DetectDevPaths(tmpDir)
+	if err != nil {
+		t.Fatalf("DetectDevPaths failed: %v", err)
+	}
+
+	installed := make(map[string]string)
+	for _, p := range paths {
+		for _, pkg := range p.Packages {
+			if pkg.Lock != nil {
+				installed[pkg.Name] = pkg.Lock.InstalledFrom
+			}
+		}
+	}
+	if installed["acme/foo"] != "path" {
+		t.Errorf("expected acme/foo to be installed from path, got %q", installed["acme/foo"])
+	}
+	if installed["acme/bar"] != "zip" {
+		t.Errorf("expected acme/bar to be installed from zip, got %q", installed["acme/bar"])
+	}
+
+	warnings := Warnings(paths)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "acme/bar") {
+		t.Errorf("expected one warning about acme/bar, got %v", warnings)
+	}
+}
+
+func TestDetectDevPaths_PackageDetails(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	packages := map[string]string{
+		"packages/site-package/composer.json": `{
+	"name": "acme/site-package",
+	"type": "typo3-cms-extension",
+	"description": "Site package",
+	"extra": {"branch-alias": {"dev-main": "2.x-dev"}}
+}`,
+		"packages/tools/composer.json":  `{"name": "acme/tools", "version": "1.0.0"}`,
+		"packages/unused/composer.json": `{"name": "acme/unused"}`,
+		"composer.json": `{
+	"repositories": [{"type": "path", "url": "packages/*"}],
+	"require": {"acme/site-package": "^2.0"},
+	"require-dev": {"acme/tools": "@dev"}
+}`,
+	}
+	for name, content := range packages {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	paths, err := DetectDevPaths(tmpDir)
+	if err != nil {
+		t.Fatalf("DetectDevPaths failed: %v", err)
+	}
+
+	found := make(map[string]model.Package)
+	for _, p := range paths {
+		for _, pkg := range p.Packages {
+			found[pkg.Name] = pkg
+		}
+	}
+
+	site := found["acme/site-package"]
+	if site.Dir != "site-package" || site.Type != "typo3-cms-extension" || site.Description != "Site package" {
+		t.Errorf("unexpected site package: %+v", site)
+	}
+	if site.BranchAlias != "dev-main as 2.x-dev" {
+		t.Errorf("expected branch alias, got %q", site.BranchAlias)
+	}
+	if !site.Required() || site.Constraint != "^2.0" || site.Dev {
+		t.Errorf("expected site package to be required with ^2.0, got %+v", site)
+	}
+
+	tools := found["acme/tools"]
+	if tools.Version != "1.0.0" || !tools.Dev || tools.Constraint != "@dev" {
+		t.Errorf("expected tools in require-dev, got %+v", tools)
+	}
+
+	if found["acme/unused"].Required() {
+		t.Error("expected acme/unused not to be required")
+	}
+	if found["acme/site-package"].Lock != nil {
+		t.Error("expected no lock information without composer.lock")
+	}
+}
+
+func TestDetectDevPaths_RepositoryTypes(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"copied/site/composer.json":   `{"name": "acme/site"}`,
+		"checkouts/lib/composer.json": `{"name": "acme/lib"}`,
+		"composer.json": `{
+	"repositories": [
+		{"type": "path", "url": "copied/*", "options": {"symlink": false, "versions": {"acme/site": "3.1.0"}}},
+		{"type": "vcs", "url": "checkouts/lib"},
+		{"type": "vcs", "url": "https://github.com/acme/remote"},
+		{"type": "artifact", "url": "artifacts"}
+	]
+}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, "artifacts"), 0755); err != nil {
+		t.Fatalf("failed to create artifacts dir: %v", err)
+	}
+
+	paths, err := DetectDevPaths(tmpDir)
+	if err != nil {
+		t.Fatalf("DetectDevPaths failed: %v", err)
+	}
+
+	byType := make(map[string]model.DevPath)
+	for _, p := range paths {
+		byType[p.Type] = p
+	}
+	if len(byType) != 3 {
+		t.Fatalf("expected path, vcs and artifact dev paths, got %+v", paths)
+	}
+
+	copied := byType["composer-path"]
+	if copied.UpdateHint == "" {
+		t.Error("expected copied path repository to carry an update hint")
+	}
+	if len(copied.Packages) != 1 || copied.Packages[0].Version != "3.1.0" {
+		t.Errorf("expected options.versions to set the version, got %+v", copied.Packages)
+	}
+
+	vcs := byType["composer-vcs"]
+	if vcs.Path != filepath.Join(tmpDir, "checkouts", "lib") || len(vcs.Packages) != 1 || vcs.Packages[0].Name != "acme/lib" {
+		t.Errorf("unexpected vcs dev path: %+v", vcs)
+	}
+
+	if byType["composer-artifact"].Path != filepath.Join(tmpDir, "artifacts") {
+		t.Errorf("unexpected artifact dev path: %+v", byType["composer-artifact"])
+	}
+}
+
+func TestDetectProjectDevPaths_ComposerRoot(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"app/packages/site/composer.json": `{"name": "acme/site"}`,
+		"external/ext1/composer.json":     `{"name": "acme/ext1"}`,
+		"app/composer.json": `{
+	"repositories": [{"type": "path", "url": "packages/*"}],
+	"config": {"vendor-dir": "libs"}
+}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	link := filepath.Join(tmpDir, "app", "libs", "acme", "ext1")
+	if err := os.MkdirAll(filepath.Dir(link), 0755); err != nil {
+		t.Fatalf("failed to create vendor dir: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(tmpDir, "external", "ext1"), link); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	paths, err := DetectProjectDevPaths(&model.Project{Path: tmpDir, ComposerRoot: "app"})
+	if err != nil {
+		t.Fatalf("DetectProjectDevPaths failed: %v", err)
+	}
+
+	byType := make(map[string]model.DevPath)
+	for _, p := range paths {
+		byType[p.Type] = p
+	}
+
+	repo := byType["composer-path"]
+	if repo.Path != filepath.Join(tmpDir, "app", "packages", "site") || repo.Source != filepath.Join("app", "composer.json") {
+		t.Errorf("expected path repository relative to composer_root, got %+v", repo)
+	}
+
+	symlink := byType["symlink"]
+	if symlink.Path != filepath.Join(tmpDir, "external", "ext1") || symlink.Source != filepath.Join("app", "libs", "acme", "ext1") {
+		t.Errorf("expected symlink in config.vendor-dir, got %+v", symlink)
+	}
+}
+
+func TestDetectProjectDevPaths_ComposerParseErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"packages/site/composer.json": `{"name": "acme/site"}`,
+		"composer.json":               `{"repositories": {"local": {"type": "path", "url": "packages/*"}}, "require": {"acme/site": "@dev"}}`,
+		"composer.lock":               `{"packages": {`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	project := &model.Project{Path: tmpDir}
+	paths, err := DetectProjectDevPaths(project)
+	if err != nil {
+		t.Fatalf("DetectProjectDevPaths failed: %v", err)
+	}
+
+	// Object-form repositories are read like the list form
+	if len(paths) != 1 || paths[0].Type != "composer-path" || len(paths[0].Packages) != 1 || paths[0].Packages[0].Constraint != "@dev" {
+		t.Errorf("expected the path repository package with its constraint, got %+v", paths)
+	}
+	if len(project.Warnings) != 1 || !strings.HasPrefix(project.Warnings[0], "failed to parse composer.lock") {
+		t.Errorf("expected a warning about composer.lock, got %v", project.Warnings)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "composer.json"), []byte(`{"repositories": "nope"}`), 0644); err != nil {
+		t.Fatalf("failed to write composer.json: %v", err)
+	}
+	project = &model.Project{Path: tmpDir}
+	if _, err := DetectProjectDevPaths(project); err != nil {
+		t.Fatalf("DetectProjectDevPaths failed: %v", err)
+	}
+	if len(project.Warnings) != 2 || !strings.HasPrefix(project.Warnings[0], "failed to parse composer.json") {
+		t.Errorf("expected warnings about composer.json and composer.lock, got %v", project.Warnings)
+	}
+}
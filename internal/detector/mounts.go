@@ -5,74 +5,47 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/dkd-dobberkau/ddev-explain/internal/compose"
 	"github.com/dkd-dobberkau/ddev-explain/internal/model"
-	"gopkg.in/yaml.v3"
 )
 
-// DockerCompose represents relevant parts of docker-compose files
-type DockerCompose struct {
-	Services map[string]struct {
-		Volumes []string `yaml:"volumes"`
-	} `yaml:"services"`
-}
-
-func detectMounts(projectPath string) ([]model.DevPath, error) {
+// detectMounts finds bind mounts outside the project in the compose files,
+// and returns the errors of files it could not load
+func detectMounts(projectPath string, env map[string]string) ([]model.DevPath, []error, error) {
 	var devPaths []model.DevPath
 
-	// Find docker-compose.*.yaml files
-	ddevDir := filepath.Join(projectPath, ".ddev")
-	entries, err := os.ReadDir(ddevDir)
+	files, loadErrs, err := compose.LoadAll(filepath.Join(projectPath, ".ddev"), env)
 	if err != nil {
-		return devPaths, err
+		return devPaths, nil, err
 	}
 
-	for _, entry := range entries {
-		if strings.HasPrefix(entry.Name(), "docker-compose.") &&
-			strings.HasSuffix(entry.Name(), ".yaml") &&
-			entry.Name() != "docker-compose.yaml" {
-
-			filePath := filepath.Join(ddevDir, entry.Name())
-			mounts, err := parseDockerCompose(filePath, projectPath)
-			if err == nil {
-				devPaths = append(devPaths, mounts...)
-			}
-		}
+	for _, file := range files {
+		devPaths = append(devPaths, mountsFromCompose(file, projectPath)...)
 	}
 
-	return devPaths, nil
+	return devPaths, loadErrs, nil
 }
 
-func parseDockerCompose(filePath, projectPath string) ([]model.DevPath, error) {
+func mountsFromCompose(file *compose.File, projectPath string) []model.DevPath {
 	var devPaths []model.DevPath
 
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, err
-	}
-
-	var dc DockerCompose
-	if err := yaml.Unmarshal(data, &dc); err != nil {
-		return nil, err
-	}
-
-	for _, svc := range dc.Services {
-		for _, vol := range svc.Volumes {
-			parts := strings.SplitN(vol, ":", 2)
-			if len(parts) < 2 {
+	for _, name := range file.ServiceNames() {
+		for _, vol := range file.Services[name].Volumes {
+			// Skip named volumes and tmpfs
+			if vol.Type != "bind" || vol.Source == "" || vol.Target == "" {
 				continue
 			}
 
-			hostPath := parts[0]
-			containerPath := parts[1]
-
-			// Skip non-path volumes
-			if !strings.HasPrefix(hostPath, ".") && !strings.HasPrefix(hostPath, "/") {
-				continue
+			hostPath := vol.Source
+			if strings.HasPrefix(hostPath, "~/") {
+				if home, err := os.UserHomeDir(); err == nil {
+					hostPath = filepath.Join(home, hostPath[2:])
+				}
 			}
 
 			absPath := hostPath
 			if !filepath.IsAbs(hostPath) {
-				absPath = filepath.Join(filepath.Dir(filePath), hostPath)
+				absPath = filepath.Join(filepath.Dir(file.Path), hostPath)
 			}
 			absPath, _ = filepath.Abs(absPath)
 
@@ -81,12 +54,14 @@ func parseDockerCompose(filePath, projectPath string) ([]model.DevPath, error) {
 				devPaths = append(devPaths, model.DevPath{
 					Path:        absPath,
 					Type:        "mount",
-					Source:      filepath.Base(filePath),
-					MountTarget: containerPath,
+					Source:      filepath.Base(file.Path),
+					MountTarget: vol.Target,
+					ReadOnly:    vol.ReadOnly,
+					Propagation: vol.Propagation,
 				})
 			}
 		}
 	}
 
-	return devPaths, nil
+	return devPaths
 }
@@ -0,0 +1,97 @@
+package detector
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/composer"
+	"github.com/dkd-dobberkau/ddev-explain/internal/model"
+)
+
+// PHPVersions holds the PHP version DDEV runs and the PHP versions and
+// constraints Composer works with; empty fields are not configured
+type PHPVersions struct {
+	DDEV         string // php_version in .ddev/config.yaml
+	Require      string // require.php in composer.json
+	Platform     string // config.platform.php in composer.json
+	LockPlatform string // platform.php in composer.lock
+}
+
+// DetectPHPVersions reads the PHP versions from the project config and the
+// composer.json and composer.lock below composer_root. Missing files are
+// not an error, but files that cannot be parsed are.
+func DetectPHPVersions(project *model.Project) (PHPVersions, error) {
+	versions := PHPVersions{DDEV: project.PHPVersion}
+	composerRoot := composer.Root(project.Path, project.ComposerRoot)
+
+	c, err := composer.ParseComposerJSON(composerRoot)
+	switch {
+	case err == nil:
+		versions.Require = c.Require["php"]
+		versions.Platform = c.Config.Platform["php"]
+	case !os.IsNotExist(err):
+		return versions, composerParseError(project.Path, composerRoot, "composer.json", err)
+	}
+
+	lock, err := composer.ParseLock(composerRoot)
+	if err != nil {
+		return versions, composerParseError(project.Path, composerRoot, "composer.lock", err)
+	}
+	if lock != nil {
+		versions.LockPlatform = lock.Platform["php"]
+	}
+	return versions, nil
+}
+
+// CheckPHPVersions reports where the PHP versions disagree, e.g. when DDEV
+// runs a PHP version composer.json does not allow
+func CheckPHPVersions(v PHPVersions) []string {
+	var warnings []string
+
+	if v.DDEV != "" && v.Require != "" && !allowsRelease(v.Require, v.DDEV) {
+		warnings = append(warnings, fmt.Sprintf("DDEV runs PHP %s but composer.json requires php %s", v.DDEV, v.Require))
+	}
+	if v.Platform != "" && v.Require != "" {
+		if ok, err := composer.Satisfies(v.Require, v.Platform); err == nil && !ok {
+			warnings = append(warnings, fmt.Sprintf("config.platform.php %s does not satisfy php %s required in composer.json", v.Platform, v.Require))
+		}
+	}
+	if v.Platform != "" && v.DDEV != "" && minorVersion(v.Platform) != minorVersion(v.DDEV) {
+		warnings = append(warnings, fmt.Sprintf("composer resolves dependencies for PHP %s (config.platform.php) but DDEV runs PHP %s", v.Platform, v.DDEV))
+	}
+	if v.LockPlatform != "" && v.Require != "" && v.LockPlatform != v.Require {
+		warnings = append(warnings, fmt.Sprintf("composer.lock was created for php %s but composer.json requires php %s; composer.lock is out of date", v.LockPlatform, v.Require))
+		if v.DDEV != "" && !allowsRelease(v.LockPlatform, v.DDEV) {
+			warnings = append(warnings, fmt.Sprintf("DDEV runs PHP %s but composer.lock requires php %s", v.DDEV, v.LockPlatform))
+		}
+	}
+
+	return warnings
+}
+
+// allowsRelease reports whether the constraint allows any patch release of
+// a PHP version like 8.1, which DDEV updates to the latest patch. An
+// unparsable constraint is not reported.
+func allowsRelease(constraint, release string) bool {
+	for _, patch := range []string{".0", ".99"} {
+		v := release
+		if strings.Count(release, ".") == 1 {
+			v += patch
+		}
+		ok, err := composer.Satisfies(constraint, v)
+		if err != nil || ok {
+			return true
+		}
+	}
+	return false
+}
+
+// minorVersion returns the major.minor part of a version
+func minorVersion(v string) string {
+	parts := strings.SplitN(v, ".", 3)
+	if len(parts) < 2 {
+		return v
+	}
+	return parts[0] + "." + parts[1]
+}
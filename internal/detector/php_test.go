@@ -0,0 +1,90 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/model"
+)
+
+func TestDetectPHPVersions(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"app/composer.json": `{"require": {"php": "^8.2"}, "config": {"platform": {"php": "8.2.0"}}}`,
+		"app/composer.lock": `{"packages": [], "platform": {"php": "^8.1"}}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	versions, err := DetectPHPVersions(&model.Project{Path: tmpDir, ComposerRoot: "app", PHPVersion: "8.1"})
+	if err != nil {
+		t.Fatalf("DetectPHPVersions failed: %v", err)
+	}
+	want := PHPVersions{DDEV: "8.1", Require: "^8.2", Platform: "8.2.0", LockPlatform: "^8.1"}
+	if versions != want {
+		t.Errorf("expected %+v, got %+v", want, versions)
+	}
+}
+
+func TestDetectPHPVersions_ObjectRepositoriesAndParseErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+	composerJSON := filepath.Join(tmpDir, "composer.json")
+	if err := os.WriteFile(composerJSON, []byte(`{"require":{"php":"^8.2"},"repositories":{"local":{"type":"path","url":"packages/*"}}}`), 0644); err != nil {
+		t.Fatalf("failed to write composer.json: %v", err)
+	}
+	project := &model.Project{Path: tmpDir, PHPVersion: "8.1"}
+
+	versions, err := DetectPHPVersions(project)
+	if err != nil {
+		t.Fatalf("DetectPHPVersions failed: %v", err)
+	}
+	if versions.Require != "^8.2" || len(CheckPHPVersions(versions)) != 1 {
+		t.Errorf("expected require.php ^8.2 to mismatch PHP 8.1, got %+v", versions)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "composer.lock"), []byte(`{"platform": `), 0644); err != nil {
+		t.Fatalf("failed to write composer.lock: %v", err)
+	}
+	if _, err := DetectPHPVersions(project); err == nil || !strings.Contains(err.Error(), "composer.lock") {
+		t.Errorf("expected an error for the broken composer.lock, got %v", err)
+	}
+
+	if err := os.WriteFile(composerJSON, []byte(`{"require": ["php"]}`), 0644); err != nil {
+		t.Fatalf("failed to write composer.json: %v", err)
+	}
+	if _, err := DetectPHPVersions(project); err == nil || !strings.Contains(err.Error(), "composer.json") {
+		t.Errorf("expected an error for the broken composer.json, got %v", err)
+	}
+}
+
+func TestCheckPHPVersions(t *testing.T) {
+	consistent := PHPVersions{DDEV: "8.2", Require: ">=8.1", Platform: "8.2.0", LockPlatform: ">=8.1"}
+	if warnings := CheckPHPVersions(consistent); len(warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", warnings)
+	}
+
+	warnings := CheckPHPVersions(PHPVersions{DDEV: "8.1", Require: "^8.2", Platform: "8.2.0", LockPlatform: "^8.1"})
+	expected := []string{
+		"DDEV runs PHP 8.1 but composer.json requires php ^8.2",
+		"composer resolves dependencies for PHP 8.2.0",
+		"composer.lock was created for php ^8.1",
+	}
+	if len(warnings) != len(expected) {
+		t.Fatalf("expected %d warnings, got %v", len(expected), warnings)
+	}
+	for i, w := range expected {
+		if !strings.HasPrefix(warnings[i], w) {
+			t.Errorf("expected warning starting with %q, got %q", w, warnings[i])
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package dockerfile
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+)
+
+// Instruction is a single Dockerfile instruction with continuations joined
+type Instruction struct {
+	Command string // Upper-cased, e.g. "RUN"
+	Args    string
+	Line    int // Line the instruction starts on
+}
+
+// Package is a package installed by a RUN instruction
+type Package struct {
+	Manager string // "apt", "apk", "pecl", "npm", "composer"
+	Name    string
+	Line    int
+}
+
+// Var is an ARG or ENV declaration; ARGs without default have an empty Value
+type Var struct {
+	Key   string
+	Value string
+	Line  int
+}
+
+// Copy is a COPY or ADD instruction
+type Copy struct {
+	Sources     []string
+	Destination string
+	Line        int
+}
+
+// Analysis summarizes what a Dockerfile adds to an image
+type Analysis struct {
+	Packages []Package
+	Args     []Var
+	Env      []Var
+	Copies   []Copy
+}
+
+// ParseFile reads the instructions of a Dockerfile
+func ParseFile(path string) ([]Instruction, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(data)), nil
+}
+
+// Parse splits a Dockerfile into instructions. Comments and blank lines are
+// dropped and lines ending in a backslash are joined with the next one.
+func Parse(content string) []Instruction {
+	var instructions []Instruction
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		start := i + 1
+
+		for strings.HasSuffix(line, `\`) && i+1 < len(lines) {
+			line = strings.TrimSpace(strings.TrimSuffix(line, `\`))
+			i++
+			next := strings.TrimSpace(lines[i])
+			// Comments inside a continuation are skipped by Docker too
+			if strings.HasPrefix(next, "#") {
+				line += `\`
+				continue
+			}
+			line += " " + next
+		}
+
+		command, args, _ := strings.Cut(line, " ")
+		instructions = append(instructions, Instruction{
+			Command: strings.ToUpper(command),
+			Args:    strings.TrimSpace(args),
+			Line:    start,
+		})
+	}
+
+	return instructions
+}
+
+// Analyze extracts installed packages, ARG/ENV values and copied files
+func Analyze(instructions []Instruction) *Analysis {
+	analysis := &Analysis{}
+
+	for _, ins := range instructions {
+		switch ins.Command {
+		case "RUN":
+			analysis.Packages = append(analysis.Packages, runPackages(ins)...)
+		case "ARG":
+			key, value, _ := strings.Cut(ins.Args, "=")
+			analysis.Args = append(analysis.Args, Var{Key: key, Value: unquote(value), Line: ins.Line})
+		case "ENV":
+			analysis.Env = append(analysis.Env, envVars(ins)...)
+		case "COPY", "ADD":
+			if c, ok := copyPaths(ins); ok {
+				analysis.Copies = append(analysis.Copies, c)
+			}
+		}
+	}
+
+	return analysis
+}
+
+// runPackages finds package manager invocations in a RUN instruction
+func runPackages(ins Instruction) []Package {
+	var packages []Package
+
+	for _, words := range shellCommands(ins.Args) {
+		// Skip leading VAR=value assignments and sudo
+		for len(words) > 0 && (strings.Contains(words[0], "=") || words[0] == "sudo") {
+			words = words[1:]
+		}
+		if len(words) < 2 {
+			continue
+		}
+
+		var manager string
+		var args []string
+		var ok bool
+		switch words[0] {
+		case "apt-get", "apt":
+			manager = "apt"
+			args, ok = subcommand(words[1:], "install")
+		case "apk":
+			manager = "apk"
+			args, ok = subcommand(words[1:], "add")
+		case "pecl":
+			manager = "pecl"
+			args, ok = subcommand(words[1:], "install")
+		case "npm":
+			manager = "npm"
+			if args, ok = subcommand(words[1:], "install"); !ok {
+				args, ok = subcommand(words[1:], "i")
+			}
+		case "yarn":
+			manager = "npm"
+			args, ok = subcommand(words[1:], "global", "add")
+		case "composer":
+			manager = "composer"
+			args, ok = subcommand(words[1:], "global", "require")
+		}
+		if !ok {
+			continue
+		}
+
+		for i := 0; i < len(args); i++ {
+			arg := args[i]
+			if arg == "-o" {
+				i++ // apt-get -o Option=value
+				continue
+			}
+			if strings.HasPrefix(arg, "-") {
+				continue
+			}
+			if strings.ContainsAny(arg, "<>") {
+				break // redirection ends the argument list
+			}
+			packages = append(packages, Package{Manager: manager, Name: arg, Line: ins.Line})
+		}
+	}
+
+	return packages
+}
+
+// subcommand matches the subcommand words that follow a tool's name, as in
+// "apt-get -y install" or "apk --no-cache add", and returns the arguments
+// after them. Options before each subcommand word are skipped.
+func subcommand(words []string, names ...string) ([]string, bool) {
+	for _, name := range names {
+		for len(words) > 0 && strings.HasPrefix(words[0], "-") {
+			if words[0] == "-o" && len(words) > 1 {
+				words = words[1:] // apt-get -o Option=value
+			}
+			words = words[1:]
+		}
+		if len(words) == 0 || words[0] != name {
+			return nil, false
+		}
+		words = words[1:]
+	}
+	return words, true
+}
+
+// shellCommands splits a shell command line on &&, ||, ; and | into the
+// words of each simple command
+func shellCommands(line string) [][]string {
+	var commands [][]string
+	var words []string
+	var word strings.Builder
+	var quote rune
+	inWord := false
+
+	flushWord := func() {
+		if inWord {
+			words = append(words, word.String())
+			word.Reset()
+			inWord = false
+		}
+	}
+	flushCommand := func() {
+		flushWord()
+		if len(words) > 0 {
+			commands = append(commands, words)
+			words = nil
+		}
+	}
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			flushWord()
+		case r == ';' || r == '|' || r == '&':
+			// && and || are two characters; a single & backgrounds
+			if i+1 < len(runes) && runes[i+1] == r {
+				i++
+			}
+			flushCommand()
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	flushCommand()
+
+	return commands
+}
+
+// envVars parses "ENV KEY=VALUE ..." and the legacy "ENV KEY VALUE"
+func envVars(ins Instruction) []Var {
+	var vars []Var
+
+	words := shellCommands(ins.Args)
+	if len(words) == 0 {
+		return vars
+	}
+	if !strings.Contains(words[0][0], "=") {
+		key, value, _ := strings.Cut(ins.Args, " ")
+		return append(vars, Var{Key: key, Value: strings.TrimSpace(value), Line: ins.Line})
+	}
+
+	for _, word := range words[0] {
+		key, value, _ := strings.Cut(word, "=")
+		vars = append(vars, Var{Key: key, Value: value, Line: ins.Line})
+	}
+	return vars
+}
+
+// copyPaths parses COPY/ADD in shell or JSON form, ignoring --flags
+func copyPaths(ins Instruction) (Copy, bool) {
+	var paths []string
+
+	args := ins.Args
+	for strings.HasPrefix(args, "--") {
+		_, rest, _ := strings.Cut(args, " ")
+		args = strings.TrimSpace(rest)
+	}
+
+	if strings.HasPrefix(args, "[") {
+		if err := json.Unmarshal([]byte(args), &paths); err != nil {
+			return Copy{}, false
+		}
+	} else {
+		paths = strings.Fields(args)
+	}
+
+	if len(paths) < 2 {
+		return Copy{}, false
+	}
+	return Copy{
+		Sources:     paths[:len(paths)-1],
+		Destination: paths[len(paths)-1],
+		Line:        ins.Line,
+	}, true
+}
+
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,96 @@
+package dockerfile
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	content := `# syntax=docker/dockerfile:1
+ARG BASE_IMAGE
+RUN apt-get update && \
+    # install tools
+    apt-get install -y git
+
+copy ./a /b
+`
+	instructions := Parse(content)
+	if len(instructions) != 3 {
+		t.Fatalf("expected 3 instructions, got %+v", instructions)
+	}
+	if instructions[1].Command != "RUN" || instructions[1].Line != 3 {
+		t.Errorf("expected RUN on line 3, got %+v", instructions[1])
+	}
+	if instructions[1].Args != "apt-get update && apt-get install -y git" {
+		t.Errorf("expected continuation lines to be joined, got %q", instructions[1].Args)
+	}
+	if instructions[2].Command != "COPY" {
+		t.Errorf("expected instructions to be upper-cased, got %q", instructions[2].Command)
+	}
+}
+
+func TestAnalyze_Packages(t *testing.T) {
+	content := `RUN apt-get update && DEBIAN_FRONTEND=noninteractive apt-get install -y -o Dpkg::Options::="--force-confold" --no-install-recommends \
+    php${DDEV_PHP_VERSION}-ldap ghostscript >/dev/null 2>&1
+RUN pecl install redis && npm install -g @vue/cli; yarn global add gulp-cli
+RUN composer global require "drush/drush:^12"
+RUN apt-get -y install vim && apt-get -qq install -o Dpkg::Use-Pty=0 less
+RUN apk --no-cache add bash && npm -g install pnpm
+`
+	analysis := Analyze(Parse(content))
+
+	expected := []Package{
+		{Manager: "apt", Name: "php${DDEV_PHP_VERSION}-ldap", Line: 1},
+		{Manager: "apt", Name: "ghostscript", Line: 1},
+		{Manager: "pecl", Name: "redis", Line: 3},
+		{Manager: "npm", Name: "@vue/cli", Line: 3},
+		{Manager: "npm", Name: "gulp-cli", Line: 3},
+		{Manager: "composer", Name: "drush/drush:^12", Line: 4},
+		{Manager: "apt", Name: "vim", Line: 5},
+		{Manager: "apt", Name: "less", Line: 5},
+		{Manager: "apk", Name: "bash", Line: 6},
+		{Manager: "npm", Name: "pnpm", Line: 6},
+	}
+	if len(analysis.Packages) != len(expected) {
+		t.Fatalf("expected %d packages, got %+v", len(expected), analysis.Packages)
+	}
+	for i, want := range expected {
+		if analysis.Packages[i] != want {
+			t.Errorf("package %d: expected %+v, got %+v", i, want, analysis.Packages[i])
+		}
+	}
+}
+
+func TestAnalyze_ArgsEnvCopies(t *testing.T) {
+	content := `ARG BASE_IMAGE
+ARG NODE_TOOLS="yarn"
+ENV COMPOSER_HOME=/usr/local/composer PATH="$PATH:/opt/bin"
+ENV LEGACY some value
+COPY --chown=www-data ./scripts/ ./other /opt/scripts/
+ADD ["a.tar", "/tmp/"]
+`
+	analysis := Analyze(Parse(content))
+
+	if len(analysis.Args) != 2 || analysis.Args[0].Value != "" || analysis.Args[1].Value != "yarn" {
+		t.Errorf("unexpected args: %+v", analysis.Args)
+	}
+
+	if len(analysis.Env) != 3 {
+		t.Fatalf("expected 3 env vars, got %+v", analysis.Env)
+	}
+	if analysis.Env[1].Key != "PATH" || analysis.Env[1].Value != "$PATH:/opt/bin" {
+		t.Errorf("expected quoted PATH value, got %+v", analysis.Env[1])
+	}
+	if analysis.Env[2].Key != "LEGACY" || analysis.Env[2].Value != "some value" {
+		t.Errorf("expected legacy ENV form, got %+v", analysis.Env[2])
+	}
+
+	if len(analysis.Copies) != 2 {
+		t.Fatalf("expected 2 copies, got %+v", analysis.Copies)
+	}
+	if len(analysis.Copies[0].Sources) != 2 || analysis.Copies[0].Destination != "/opt/scripts/" {
+		t.Errorf("unexpected COPY: %+v", analysis.Copies[0])
+	}
+	if analysis.Copies[1].Sources[0] != "a.tar" || analysis.Copies[1].Line != 6 {
+		t.Errorf("unexpected ADD: %+v", analysis.Copies[1])
+	}
+}
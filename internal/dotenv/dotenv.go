@@ -0,0 +1,161 @@
+package dotenv
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Entry is a single KEY=VALUE assignment from an env file
+type Entry struct {
+	Key   string
+	Value string
+	Line  int
+}
+
+// ParseFile reads KEY=VALUE pairs from an env file
+func ParseFile(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(data))
+}
+
+// Parse reads KEY=VALUE pairs in the format docker compose and DDEV accept:
+// blank lines and # comments are ignored, an optional "export " prefix is
+// dropped, single-quoted values are literal, double-quoted values support
+// escapes and may span lines, and unquoted values end at " #".
+func Parse(content string) ([]Entry, error) {
+	var entries []Entry
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		key, rest, ok := strings.Cut(line, "=")
+		if !ok {
+			// A bare KEY passes the variable through from the host; nothing to record
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("line %d: invalid variable name %q", lineNo, key)
+		}
+		rest = strings.TrimLeft(rest, " \t")
+
+		var value string
+		switch {
+		case strings.HasPrefix(rest, "'"):
+			end := strings.Index(rest[1:], "'")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated single quote", lineNo)
+			}
+			value = rest[1 : end+1]
+		case strings.HasPrefix(rest, `"`):
+			// Double-quoted values may continue on the following lines
+			quoted := rest[1:]
+			for {
+				if end := closingQuote(quoted); end >= 0 {
+					value = unescape(quoted[:end])
+					break
+				}
+				if i+1 >= len(lines) {
+					return nil, fmt.Errorf("line %d: unterminated double quote", lineNo)
+				}
+				i++
+				quoted += "\n" + lines[i]
+			}
+		default:
+			value = rest
+			if idx := strings.Index(value, " #"); idx >= 0 {
+				value = value[:idx]
+			}
+			value = strings.TrimSpace(value)
+		}
+
+		entries = append(entries, Entry{Key: key, Value: value, Line: lineNo})
+	}
+
+	return entries, nil
+}
+
+// closingQuote returns the index of the first unescaped double quote
+func closingQuote(s string) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+func unescape(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 >= len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String()
+}
+
+// ToMap converts entries to a map; later entries win
+func ToMap(entries []Entry) map[string]string {
+	m := make(map[string]string, len(entries))
+	for _, e := range entries {
+		m[e.Key] = e.Value
+	}
+	return m
+}
+
+// secretMarkers are matched anywhere in a variable name
+var secretMarkers = []string{
+	"PASSWORD", "PASSWD", "SECRET", "TOKEN", "PRIVATE",
+	"CREDENTIAL", "APIKEY", "API_KEY",
+}
+
+// secretSegments are too short to match anywhere, e.g. PASS in PASSENGER or
+// AUTH in AUTHOR, so they must be a whole _-delimited part of the name
+var secretSegments = map[string]bool{
+	"PASS": true, "AUTH": true, "SALT": true, "DSN": true,
+}
+
+// LooksSecret reports whether a variable name suggests a sensitive value
+func LooksSecret(key string) bool {
+	upper := strings.ToUpper(key)
+	if strings.HasSuffix(upper, "_KEY") {
+		return true
+	}
+	for _, marker := range secretMarkers {
+		if strings.Contains(upper, marker) {
+			return true
+		}
+	}
+	for _, segment := range strings.Split(upper, "_") {
+		if secretSegments[segment] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package dotenv
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	content := `# DDEV environment
+PLAIN=value
+export EXPORTED=yes
+SPACED = padded value   # trailing comment
+HASH=abc#def
+SINGLE='literal $HOME \n'
+DOUBLE="line one\nline \"two\""
+MULTI="first
+second"
+EMPTY=
+PASSTHROUGH
+`
+	entries, err := Parse(content)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	got := ToMap(entries)
+	expected := map[string]string{
+		"PLAIN":    "value",
+		"EXPORTED": "yes",
+		"SPACED":   "padded value",
+		"HASH":     "abc#def",
+		"SINGLE":   `literal $HOME \n`,
+		"DOUBLE":   "line one\nline \"two\"",
+		"MULTI":    "first\nsecond",
+		"EMPTY":    "",
+	}
+	if len(got) != len(expected) {
+		t.Errorf("expected %d entries, got %d: %v", len(expected), len(got), got)
+	}
+	for k, v := range expected {
+		if got[k] != v {
+			t.Errorf("expected %s=%q, got %q", k, v, got[k])
+		}
+	}
+
+	if entries[0].Line != 2 {
+		t.Errorf("expected PLAIN on line 2, got %d", entries[0].Line)
+	}
+}
+
+func TestParse_Unterminated(t *testing.T) {
+	if _, err := Parse(`BROKEN="never closed`); err == nil {
+		t.Error("expected error for unterminated double quote")
+	}
+}
+
+func TestLooksSecret(t *testing.T) {
+	for _, key := range []string{"DB_PASSWORD", "STRIPE_SECRET", "GITHUB_TOKEN", "APP_KEY", "SENTRY_DSN", "SMTP_PASS", "BASIC_AUTH_USER"} {
+		if !LooksSecret(key) {
+			t.Errorf("expected %s to look secret", key)
+		}
+	}
+	for _, key := range []string{"TYPO3_CONTEXT", "APP_ENV", "DDEV_SITENAME", "PASSENGER_APP_ENV", "AUTHOR", "COMPASS_PATH", "OAUTH_CALLBACK_URL"} {
+		if LooksSecret(key) {
+			t.Errorf("expected %s not to look secret", key)
+		}
+	}
+}
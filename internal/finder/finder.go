@@ -44,12 +44,16 @@ func FindProjectUpward(startPath string) (string, error) {
 
 // FindAllProjects returns all known DDEV projects from project_list.yaml
 func FindAllProjects() ([]string, error) {
-	homeDir, err := os.UserHomeDir()
+	globalDir, err := GlobalDir()
 	if err != nil {
 		return nil, err
 	}
+	return FindAllProjectsIn(globalDir)
+}
 
-	projectListPath := filepath.Join(homeDir, ".ddev", "project_list.yaml")
+// FindAllProjectsIn returns all projects listed in globalDir/project_list.yaml
+func FindAllProjectsIn(globalDir string) ([]string, error) {
+	projectListPath := filepath.Join(globalDir, "project_list.yaml")
 	if _, err := os.Stat(projectListPath); os.IsNotExist(err) {
 		return nil, ErrNoProjectList
 	}
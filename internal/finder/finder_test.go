@@ -74,3 +74,40 @@ func TestFindAllProjects_NoGlobalConfig(t *testing.T) {
 	// We don't assert the error since it depends on the test environment
 	_ = err
 }
+
+func TestGlobalDir_DDEVHome(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("DDEV_HOME", tmpDir)
+
+	dir, err := GlobalDir()
+	if err != nil {
+		t.Fatalf("GlobalDir failed: %v", err)
+	}
+	if dir != tmpDir {
+		t.Errorf("expected '%s', got '%s'", tmpDir, dir)
+	}
+
+	if _, err := GlobalConfigPath(dir); !errors.Is(err, ErrNoGlobalConfig) {
+		t.Errorf("expected ErrNoGlobalConfig, got %v", err)
+	}
+}
+
+func TestFindAllProjectsIn(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectList := `b-project:
+  approot: /srv/b
+a-project:
+  approot: /srv/a
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "project_list.yaml"), []byte(projectList), 0644); err != nil {
+		t.Fatalf("failed to write project list: %v", err)
+	}
+
+	paths, err := FindAllProjectsIn(tmpDir)
+	if err != nil {
+		t.Fatalf("FindAllProjectsIn failed: %v", err)
+	}
+	if len(paths) != 2 || paths[0] != "/srv/a" || paths[1] != "/srv/b" {
+		t.Errorf("expected sorted project paths, got %v", paths)
+	}
+}
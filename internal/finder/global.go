@@ -0,0 +1,29 @@
+package finder
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// GlobalDir returns the DDEV global config directory. $DDEV_HOME takes
+// precedence over the default ~/.ddev.
+func GlobalDir() (string, error) {
+	if dir := os.Getenv("DDEV_HOME"); dir != "" {
+		return dir, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".ddev"), nil
+}
+
+// GlobalConfigPath returns the path to global_config.yaml in globalDir
+func GlobalConfigPath(globalDir string) (string, error) {
+	configPath := filepath.Join(globalDir, "global_config.yaml")
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return "", ErrNoGlobalConfig
+	}
+	return configPath, nil
+}
@@ -1,19 +1,99 @@
 package model
 
+import "fmt"
+
 // Project represents a complete DDEV project analysis
 type Project struct {
-	Name       string              `json:"name"`
-	Path       string              `json:"path"`
-	Type       string              `json:"type"`
-	PHPVersion string              `json:"php_version"`
-	Webserver  string              `json:"webserver"`
-	Database   Database            `json:"database"`
-	URLs       []string            `json:"urls,omitempty"`
-	NodeJS     string              `json:"nodejs,omitempty"`
-	Services   []Service           `json:"services,omitempty"`
-	DevPaths   []DevPath           `json:"dev_paths,omitempty"`
-	Commands   []Command           `json:"commands,omitempty"`
-	Hooks      map[string][]string `json:"hooks,omitempty"`
+	Name       string            `json:"name"`
+	Path       string            `json:"path"`
+	Type       string            `json:"type"`
+	PHPVersion string            `json:"php_version"`
+	Webserver  string            `json:"webserver"`
+	Database   Database          `json:"database"`
+	URLs       []string          `json:"urls,omitempty"`
+	NodeJS     string            `json:"nodejs,omitempty"`
+	Services   []Service         `json:"services,omitempty"`
+	DevPaths   []DevPath         `json:"dev_paths,omitempty"`
+	Commands   []Command         `json:"commands,omitempty"`
+	Addons     []Addon           `json:"addons,omitempty"`
+	Hooks      map[string][]Hook `json:"hooks,omitempty"` // Event -> entries in declaration order
+
+	Docroot               string        `json:"docroot,omitempty"`
+	ComposerVersion       string        `json:"composer_version,omitempty"`
+	ComposerRoot          string        `json:"composer_root,omitempty"`
+	XdebugEnabled         bool          `json:"xdebug_enabled,omitempty"`
+	XHProfMode            string        `json:"xhprof_mode,omitempty"`
+	PerformanceMode       string        `json:"performance_mode,omitempty"`
+	UploadDirs            []string      `json:"upload_dirs,omitempty"`
+	Timezone              string        `json:"timezone,omitempty"`
+	CorepackEnable        bool          `json:"corepack_enable,omitempty"`
+	WebimageExtraPackages []string      `json:"webimage_extra_packages,omitempty"`
+	DBImageExtraPackages  []string      `json:"dbimage_extra_packages,omitempty"`
+	WebEnvironment        []string      `json:"web_environment,omitempty"`
+	WebExtraExposedPorts  []ExposedPort `json:"web_extra_exposed_ports,omitempty"`
+	WebExtraDaemons       []Daemon      `json:"web_extra_daemons,omitempty"`
+	OmitContainers        []string      `json:"omit_containers,omitempty"`
+	XdebugIDELocation     string        `json:"xdebug_ide_location,omitempty"`
+	RouterHTTPPort        string        `json:"router_http_port,omitempty"`
+	RouterHTTPSPort       string        `json:"router_https_port,omitempty"`
+
+	Environment map[string][]EnvVar `json:"environment,omitempty"` // Service -> effective variables
+
+	ImageCustomizations []ImageCustomization `json:"image_customizations,omitempty"`
+	Overrides           []Override           `json:"overrides,omitempty"`
+	Providers           []Provider           `json:"providers,omitempty"`
+
+	Provenance map[string][]Origin `json:"provenance,omitempty"` // Config key -> files that set it, in merge order
+
+	Warnings []string `json:"warnings,omitempty"` // Problems found while analyzing the project
+}
+
+// Source returns the origin of the effective value for a config key
+func (p *Project) Source(key string) (Origin, bool) {
+	chain := p.Provenance[key]
+	if len(chain) == 0 {
+		return Origin{}, false
+	}
+	return chain[len(chain)-1], true
+}
+
+// Sources returns every origin contributing to the effective value of a
+// config key: the last one for scalars, all appended ones for lists
+func (p *Project) Sources(key string) []Origin {
+	chain := p.Provenance[key]
+	for i := len(chain) - 1; i >= 0; i-- {
+		if chain[i].Mode != "append" {
+			return chain[i:]
+		}
+	}
+	return chain
+}
+
+// Inherited returns the scope a config key's effective value was inherited
+// from, or "" if the project itself sets it
+func (p *Project) Inherited(key string) string {
+	origin, ok := p.Source(key)
+	if !ok {
+		return ""
+	}
+	return origin.Scope
+}
+
+// Origin records where a configuration value was set
+type Origin struct {
+	File  string `json:"file"`
+	Line  int    `json:"line,omitempty"`
+	Value string `json:"value"`
+	Mode  string `json:"mode"`            // "set", "append", "replace"
+	Scope string `json:"scope,omitempty"` // "global" (global_config.yaml), "default" (DDEV built-in) or "" (project)
+}
+
+// String returns the origin as file:line
+func (o Origin) String() string {
+	if o.Line == 0 {
+		return o.File
+	}
+	return fmt.Sprintf("%s:%d", o.File, o.Line)
 }
 
 // Database represents database configuration
@@ -22,26 +102,201 @@ type Database struct {
 	Version string `json:"version"`
 }
 
+// EnvVar is an environment variable passed to a container
+type EnvVar struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"` // file:line that set the effective value
+}
+
+// ExposedPort represents a web_extra_exposed_ports entry
+type ExposedPort struct {
+	Name          string `json:"name"`
+	ContainerPort int    `json:"container_port"`
+	HTTPPort      int    `json:"http_port,omitempty"`
+	HTTPSPort     int    `json:"https_port,omitempty"`
+}
+
+// Daemon represents a web_extra_daemons entry
+type Daemon struct {
+	Name      string `json:"name"`
+	Command   string `json:"command"`
+	Directory string `json:"directory,omitempty"`
+}
+
+// Hook is a single task run on a hook event
+type Hook struct {
+	Kind      string `json:"kind"`      // "exec", "exec-host", "composer"
+	Container string `json:"container"` // "web", "host" or the service of an exec
+	Command   string `json:"command"`
+	Source    string `json:"source"` // file:line that declared the entry
+}
+
+// ImageCustomization is a Dockerfile in .ddev/web-build or .ddev/db-build
+type ImageCustomization struct {
+	Image    string         `json:"image"`         // "web" or "db"
+	File     string         `json:"file"`          // Relative to .ddev
+	Pre      bool           `json:"pre,omitempty"` // pre.Dockerfile*, runs before DDEV's own instructions
+	Packages []ImagePackage `json:"packages,omitempty"`
+	Args     []EnvVar       `json:"args,omitempty"`
+	Env      []EnvVar       `json:"env,omitempty"`
+	Copies   []ImageCopy    `json:"copies,omitempty"`
+}
+
+// ImagePackage is a package installed by a Dockerfile RUN instruction
+type ImagePackage struct {
+	Manager string `json:"manager"` // "apt", "apk", "pecl", "npm", "composer"
+	Name    string `json:"name"`
+	Source  string `json:"source"` // file:line
+}
+
+// ImageCopy is a COPY or ADD instruction
+type ImageCopy struct {
+	From   []string `json:"from"`
+	To     string   `json:"to"`
+	Source string   `json:"source"` // file:line
+}
+
+// Override is a PHP, webserver or database config file DDEV mounts into a container
+type Override struct {
+	Kind      string    `json:"kind"` // "php", "nginx_full", "nginx", "apache", "mysql", "postgres"
+	File      string    `json:"file"` // Relative to .ddev
+	Generated bool      `json:"generated"`
+	Settings  []Setting `json:"settings,omitempty"`
+}
+
+// Setting is a key/value pair from an ini, cnf or conf file
+type Setting struct {
+	Section string `json:"section,omitempty"`
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Line    int    `json:"line"`
+}
+
+// Provider is a hosting provider integration in .ddev/providers, used by
+// ddev pull and ddev push
+type Provider struct {
+	Name        string   `json:"name"`
+	File        string   `json:"file"`                   // Relative to .ddev
+	Commands    []string `json:"commands,omitempty"`     // Defined *_command entries
+	Variables   []EnvVar `json:"variables,omitempty"`    // environment_variables
+	RequiredEnv []string `json:"required_env,omitempty"` // Variables the commands use that the file doesn't set
+	MissingEnv  []string `json:"missing_env,omitempty"`  // Required variables not set where the commands run
+	Generated   bool     `json:"generated,omitempty"`    // Unmodified example from ddev config (#ddev-generated)
+}
+
+// HasCommand reports whether the provider defines a *_command entry
+func (p Provider) HasCommand(name string) bool {
+	for _, c := range p.Commands {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
 // Service represents an additional DDEV service
 type Service struct {
-	Name   string                 `json:"name"`
-	Type   string                 `json:"type"`
-	Ports  []string               `json:"ports,omitempty"`
-	Config map[string]interface{} `json:"config,omitempty"`
+	Name          string                 `json:"name"`
+	Type          string                 `json:"type"`
+	Ports         []string               `json:"ports,omitempty"`
+	Config        map[string]interface{} `json:"config,omitempty"`
+	Command       string                 `json:"command,omitempty"`        // web-daemon: command run in the web container
+	ContainerPort int                    `json:"container_port,omitempty"` // web-daemon, web-port: port inside the web container
+	HTTPPort      int                    `json:"http_port,omitempty"`      // web-daemon, web-port: router HTTP port
+	HTTPSPort     int                    `json:"https_port,omitempty"`     // web-daemon, web-port: router HTTPS port
+	File          string                 `json:"file,omitempty"`           // Compose file defining the service, relative to .ddev
+	Addon         string                 `json:"addon,omitempty"`          // Add-on that installed the service
+	Generated     bool                   `json:"generated,omitempty"`      // File carries #ddev-generated
 }
 
 // DevPath represents a development directory
 type DevPath struct {
-	Path        string   `json:"path"`
-	Type        string   `json:"type"`          // "composer-path", "symlink", "mount", "convention"
-	Source      string   `json:"source"`        // Where detected (composer.json, docker-compose, etc.)
-	MountTarget string   `json:"mount_target,omitempty"` // If mount: target in container
-	Packages    []string `json:"packages,omitempty"`
+	Path        string    `json:"path"`
+	Type        string    `json:"type"`                   // "composer-path", "composer-vcs", "composer-artifact", "symlink", "mount", "convention"
+	Source      string    `json:"source"`                 // Where detected (composer.json, docker-compose, etc.)
+	MountTarget string    `json:"mount_target,omitempty"` // If mount: target in container
+	ReadOnly    bool      `json:"read_only,omitempty"`    // If mount: mounted read-only
+	Propagation string    `json:"propagation,omitempty"`  // If mount: bind propagation (rshared, ...)
+	Packages    []Package `json:"packages,omitempty"`
+	UpdateHint  string    `json:"update_hint,omitempty"` // How local edits reach vendor/ when they are not picked up live
+}
+
+// Package is a Composer package found in a development directory
+type Package struct {
+	Name        string       `json:"name"` // Composer name, or the directory name if composer.json has none
+	Dir         string       `json:"dir"`  // Directory name
+	Type        string       `json:"type,omitempty"`
+	Version     string       `json:"version,omitempty"`
+	BranchAlias string       `json:"branch_alias,omitempty"` // e.g. "dev-main as 2.x-dev"
+	Description string       `json:"description,omitempty"`
+	Constraint  string       `json:"constraint,omitempty"` // Constraint the root composer.json requires it with
+	Dev         bool         `json:"dev,omitempty"`        // Required in require-dev
+	Lock        *PackageLock `json:"lock,omitempty"`       // If composer-path: what composer.lock installed
+}
+
+// Required reports whether the root composer.json requires the package
+func (p Package) Required() bool {
+	return p.Constraint != ""
+}
+
+// PackageLock records how composer.lock installed a package
+type PackageLock struct {
+	Installed     bool   `json:"installed"` // Listed in composer.lock
+	Version       string `json:"version,omitempty"`
+	InstalledFrom string `json:"installed_from,omitempty"` // "path", "zip", "git", ...
+}
+
+// FromPath reports whether composer installed the package from its path repository
+func (l PackageLock) FromPath() bool {
+	return l.InstalledFrom == "path"
 }
 
 // Command represents a DDEV custom command
 type Command struct {
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
-	Path        string `json:"path"`
+	Name              string        `json:"name"`
+	Container         string        `json:"container"` // Subdirectory of .ddev/commands: host, web, db, ...
+	Description       string        `json:"description,omitempty"`
+	Usage             string        `json:"usage,omitempty"`
+	Example           string        `json:"example,omitempty"`
+	Flags             []CommandFlag `json:"flags,omitempty"`
+	FlagsError        string        `json:"flags_error,omitempty"` // ## Flags: is not valid JSON
+	ProjectTypes      []string      `json:"project_types,omitempty"`
+	OSTypes           []string      `json:"os_types,omitempty"`
+	HostBinaryExists  []string      `json:"host_binary_exists,omitempty"`
+	HostWorkingDir    bool          `json:"host_working_dir,omitempty"`
+	CanRunGlobally    bool          `json:"can_run_globally,omitempty"`
+	AutocompleteTerms []string      `json:"autocomplete_terms,omitempty"`
+	Scope             string        `json:"scope"`                    // "project" or "global"
+	Shadows           bool          `json:"shadows_global,omitempty"` // Project command hiding a global one
+	Hidden            string        `json:"hidden,omitempty"`         // Why DDEV won't offer the command here
+	Path              string        `json:"path"`
+	Addon             string        `json:"addon,omitempty"`     // Add-on that installed the command
+	Generated         bool          `json:"generated,omitempty"` // File carries #ddev-generated
+}
+
+// CommandFlag is an entry of a command's ## Flags: annotation
+type CommandFlag struct {
+	Name      string `json:"name"`
+	Shorthand string `json:"shorthand,omitempty"`
+	Usage     string `json:"usage,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Default   string `json:"default,omitempty"`
+}
+
+// Addon represents a DDEV add-on installed in the project
+type Addon struct {
+	Name       string   `json:"name"`
+	Repository string   `json:"repository,omitempty"`
+	Version    string   `json:"version,omitempty"`
+	Source     string   `json:"source"` // "manifest" or "inferred"
+	Files      []string `json:"files,omitempty"`
+	Services   []string `json:"services,omitempty"`
+	Commands   []string `json:"commands,omitempty"`
+}
+
+// DDEVFile is a file below .ddev with its ownership state
+type DDEVFile struct {
+	Path   string `json:"path"`   // Relative to .ddev
+	Status string `json:"status"` // "generated", "user-modified", "unknown"
 }
@@ -1,8 +1,373 @@
 package output
 
-import "github.com/dkd-dobberkau/ddev-explain/internal/model"
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dkd-dobberkau/ddev-explain/internal/model"
+)
 
 // Formatter defines the interface for output formatters
 type Formatter interface {
 	Format(project *model.Project) (string, error)
 }
+
+// sourceOf returns the distinct file:line origins of the effective values
+// for the given config keys
+func sourceOf(project *model.Project, keys ...string) string {
+	var sources []string
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		for _, origin := range project.Sources(key) {
+			if seen[origin.String()] {
+				continue
+			}
+			seen[origin.String()] = true
+			sources = append(sources, origin.String())
+		}
+	}
+	return strings.Join(sources, ", ")
+}
+
+// scopeOf returns where the effective values of the given config keys were
+// inherited from ("global", "default"), "partly ..." when only some of them
+// were inherited, or "" if the project sets them itself
+func scopeOf(project *model.Project, keys ...string) string {
+	var scopes []string
+	for _, key := range keys {
+		if _, ok := project.Source(key); ok {
+			scopes = append(scopes, project.Inherited(key))
+		}
+	}
+
+	var scope string
+	for _, s := range scopes {
+		if s != "" {
+			scope = s
+			break
+		}
+	}
+	if scope == "" {
+		return ""
+	}
+
+	for _, s := range scopes {
+		if s != scope {
+			return "partly " + scope
+		}
+	}
+	return scope
+}
+
+func enabledOrDisabled(b bool) string {
+	if b {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+// exposedPortSummary describes how a container port is published by the router
+func exposedPortSummary(p model.ExposedPort) string {
+	summary := fmt.Sprintf("container %d", p.ContainerPort)
+	if p.HTTPPort != 0 {
+		summary += fmt.Sprintf(", http %d", p.HTTPPort)
+	}
+	if p.HTTPSPort != 0 {
+		summary += fmt.Sprintf(", https %d", p.HTTPSPort)
+	}
+	return summary
+}
+
+// serviceDetails summarizes the command and ports of a web daemon or port
+func serviceDetails(svc model.Service) string {
+	var parts []string
+	if svc.Command != "" {
+		parts = append(parts, svc.Command)
+	}
+	if svc.ContainerPort != 0 {
+		parts = append(parts, exposedPortSummary(model.ExposedPort{
+			ContainerPort: svc.ContainerPort,
+			HTTPPort:      svc.HTTPPort,
+			HTTPSPort:     svc.HTTPSPort,
+		}))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// mountSummary describes the container target and flags of a mount
+func mountSummary(dp model.DevPath) string {
+	var flags []string
+	if dp.ReadOnly {
+		flags = append(flags, "ro")
+	}
+	if dp.Propagation != "" {
+		flags = append(flags, dp.Propagation)
+	}
+	if len(flags) == 0 {
+		return dp.MountTarget
+	}
+	return fmt.Sprintf("%s (%s)", dp.MountTarget, strings.Join(flags, ", "))
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// customServices returns the services not installed by an add-on
+func customServices(project *model.Project) []model.Service {
+	var services []model.Service
+	for _, svc := range project.Services {
+		if svc.Addon == "" {
+			services = append(services, svc)
+		}
+	}
+	return services
+}
+
+// customCommands returns the commands not installed by an add-on
+func customCommands(project *model.Project) []model.Command {
+	var commands []model.Command
+	for _, cmd := range project.Commands {
+		if cmd.Addon == "" {
+			commands = append(commands, cmd)
+		}
+	}
+	return commands
+}
+
+// addonSummary describes an add-on's origin, e.g. "ddev/ddev-redis v1.0.4"
+func addonSummary(addon model.Addon) string {
+	parts := []string{}
+	if addon.Repository != "" {
+		parts = append(parts, addon.Repository)
+	}
+	if addon.Version != "" {
+		parts = append(parts, addon.Version)
+	}
+	if addon.Source == "inferred" {
+		parts = append(parts, "no metadata")
+	}
+	return strings.Join(parts, " ")
+}
+
+// generatedTag marks entries whose file DDEV may overwrite
+func generatedTag(generated bool) string {
+	if generated {
+		return " [generated]"
+	}
+	return ""
+}
+
+// flagSummary lists command flags as "--name/-n", e.g. "--force/-f, --dry-run"
+func flagSummary(flags []model.CommandFlag) string {
+	names := make([]string, 0, len(flags))
+	for _, f := range flags {
+		name := "--" + f.Name
+		if f.Shorthand != "" {
+			name += "/-" + f.Shorthand
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// commandContainers groups commands by the container they run in, host
+// and web first, then the remaining containers alphabetically
+func commandContainers(commands []model.Command) ([]string, map[string][]model.Command) {
+	byContainer := make(map[string][]model.Command)
+	for _, cmd := range commands {
+		byContainer[cmd.Container] = append(byContainer[cmd.Container], cmd)
+	}
+
+	var containers []string
+	for _, c := range []string{"host", "web"} {
+		if _, ok := byContainer[c]; ok {
+			containers = append(containers, c)
+		}
+	}
+	for _, c := range sortedKeys(byContainer) {
+		if c != "host" && c != "web" {
+			containers = append(containers, c)
+		}
+	}
+	return containers, byContainer
+}
+
+// commandTags marks global, shadowing and generated commands
+func commandTags(cmd model.Command) string {
+	tags := ""
+	if cmd.Scope == "global" {
+		tags += " [global]"
+	}
+	if cmd.Shadows {
+		tags += " [overrides global]"
+	}
+	return tags + generatedTag(cmd.Generated)
+}
+
+// hookSummary renders a hook entry, prefixed with where it runs unless
+// that is the web container
+func hookSummary(h model.Hook) string {
+	switch {
+	case h.Kind == "composer":
+		return "(composer) " + h.Command
+	case h.Container != "web":
+		return fmt.Sprintf("(%s) %s", h.Container, h.Command)
+	}
+	return h.Command
+}
+
+// packageGroups lists image packages per manager in first-seen order, e.g.
+// ["apt: git, unzip", "npm: yarn"]
+func packageGroups(packages []model.ImagePackage) []string {
+	var managers []string
+	names := make(map[string][]string)
+	for _, p := range packages {
+		if _, ok := names[p.Manager]; !ok {
+			managers = append(managers, p.Manager)
+		}
+		names[p.Manager] = append(names[p.Manager], p.Name)
+	}
+
+	groups := make([]string, 0, len(managers))
+	for _, m := range managers {
+		groups = append(groups, m+": "+strings.Join(names[m], ", "))
+	}
+	return groups
+}
+
+// imageFileLabel describes a Dockerfile, e.g. "web-build/pre.Dockerfile (web, before DDEV's build)"
+func imageFileLabel(c model.ImageCustomization) string {
+	if c.Pre {
+		return fmt.Sprintf("%s (%s, before DDEV's build)", c.File, c.Image)
+	}
+	return fmt.Sprintf("%s (%s)", c.File, c.Image)
+}
+
+// argDeclaration renders a Dockerfile ARG, with its default if it has one.
+// Secret defaults arrive here already masked unless --show-secrets is set.
+func argDeclaration(v model.EnvVar) string {
+	if v.Value == "" {
+		return v.Key
+	}
+	return v.Key + "=" + v.Value
+}
+
+// overrideLabel describes an override file; webserver configs also say
+// whether DDEV still owns them
+func overrideLabel(o model.Override) string {
+	switch o.Kind {
+	case "nginx_full", "nginx", "apache":
+		if o.Generated {
+			return fmt.Sprintf("%s (%s, #ddev-generated: DDEV overwrites it)", o.File, o.Kind)
+		}
+		return fmt.Sprintf("%s (%s, customized)", o.File, o.Kind)
+	}
+	return fmt.Sprintf("%s (%s)", o.File, o.Kind)
+}
+
+// settingLabel renders a setting as "[section] key = value"
+func settingLabel(s model.Setting) string {
+	label := s.Key
+	if s.Value != "" {
+		label += " = " + s.Value
+	}
+	if s.Section != "" {
+		label = "[" + s.Section + "] " + label
+	}
+	return label
+}
+
+// visibleProviders returns the providers to list and, outside verbose
+// mode, the names of the unmodified example providers ddev config installs
+func visibleProviders(providers []model.Provider, verbose bool) (shown []model.Provider, examples []string) {
+	for _, p := range providers {
+		if p.Generated && !verbose {
+			examples = append(examples, p.Name)
+			continue
+		}
+		shown = append(shown, p)
+	}
+	return shown, examples
+}
+
+// providerStatus is "example" for unmodified generated providers, whose
+// missing settings are expected, else whether the provider is complete
+func providerStatus(p model.Provider, problems []string) string {
+	switch {
+	case p.Generated:
+		return "example"
+	case len(problems) > 0:
+		return "incomplete"
+	default:
+		return "complete"
+	}
+}
+
+// providerTargets lists the ddev pull/push targets a provider supports and
+// what keeps it from being complete
+func providerTargets(p model.Provider) (targets, problems []string) {
+	for _, op := range []string{"pull", "push"} {
+		var parts []string
+		for _, kind := range []string{"db", "files"} {
+			if p.HasCommand(kind + "_" + op + "_command") {
+				parts = append(parts, kind)
+			} else if op == "pull" {
+				problems = append(problems, "no "+kind+"_pull_command")
+			}
+		}
+		if len(parts) > 0 {
+			targets = append(targets, fmt.Sprintf("ddev %s %s (%s)", op, p.Name, strings.Join(parts, ", ")))
+		}
+	}
+	for _, key := range p.MissingEnv {
+		problems = append(problems, key+" not set")
+	}
+	return targets, problems
+}
+
+// packageSummary describes a local package: its version, whether the root
+// project requires it and how composer.lock installed it
+func packageSummary(pkg model.Package) string {
+	var parts []string
+	switch {
+	case pkg.BranchAlias != "":
+		parts = append(parts, pkg.BranchAlias)
+	case pkg.Version != "":
+		parts = append(parts, pkg.Version)
+	}
+
+	switch {
+	case pkg.Dev:
+		parts = append(parts, "require-dev "+pkg.Constraint)
+	case pkg.Required():
+		parts = append(parts, "required "+pkg.Constraint)
+	default:
+		parts = append(parts, "not required")
+	}
+
+	if pkg.Lock != nil {
+		switch {
+		case !pkg.Lock.Installed:
+			parts = append(parts, "not in composer.lock")
+		case pkg.Lock.FromPath():
+			parts = append(parts, "installed from path")
+		default:
+			parts = append(parts, fmt.Sprintf("installed from %s %s, not this path", pkg.Lock.InstalledFrom, pkg.Lock.Version))
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// installedElsewhere reports whether a path package came from another source
+func installedElsewhere(pkg model.Package) bool {
+	return pkg.Lock != nil && pkg.Lock.Installed && !pkg.Lock.FromPath()
+}
@@ -6,14 +6,21 @@ import (
 	"github.com/dkd-dobberkau/ddev-explain/internal/model"
 )
 
-type JSONFormatter struct{}
+type JSONFormatter struct {
+	Provenance bool
+}
 
-func NewJSONFormatter() *JSONFormatter {
-	return &JSONFormatter{}
+func NewJSONFormatter(provenance bool) *JSONFormatter {
+	return &JSONFormatter{Provenance: provenance}
 }
 
 func (f *JSONFormatter) Format(project *model.Project) (string, error) {
-	data, err := json.MarshalIndent(project, "", "  ")
+	out := *project
+	if !f.Provenance {
+		out.Provenance = nil
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
 	if err != nil {
 		return "", err
 	}
@@ -8,11 +8,12 @@ import (
 )
 
 type MarkdownFormatter struct {
-	Verbose bool
+	Verbose    bool
+	Provenance bool
 }
 
-func NewMarkdownFormatter(verbose bool) *MarkdownFormatter {
-	return &MarkdownFormatter{Verbose: verbose}
+func NewMarkdownFormatter(verbose, provenance bool) *MarkdownFormatter {
+	return &MarkdownFormatter{Verbose: verbose, Provenance: provenance}
 }
 
 func (f *MarkdownFormatter) Format(project *model.Project) (string, error) {
@@ -21,15 +22,149 @@ func (f *MarkdownFormatter) Format(project *model.Project) (string, error) {
 	sb.WriteString(fmt.Sprintf("# DDEV Project: %s\n\n", project.Name))
 
 	sb.WriteString("## Configuration\n\n")
-	sb.WriteString("| Setting | Value |\n")
-	sb.WriteString("|---------|-------|\n")
-	sb.WriteString(fmt.Sprintf("| Type | %s |\n", project.Type))
-	sb.WriteString(fmt.Sprintf("| Path | `%s` |\n", project.Path))
-	sb.WriteString(fmt.Sprintf("| PHP | %s |\n", project.PHPVersion))
-	sb.WriteString(fmt.Sprintf("| Webserver | %s |\n", project.Webserver))
-	sb.WriteString(fmt.Sprintf("| Database | %s %s |\n", project.Database.Type, project.Database.Version))
+	if f.Provenance {
+		sb.WriteString("| Setting | Value | Source |\n")
+		sb.WriteString("|---------|-------|--------|\n")
+	} else {
+		sb.WriteString("| Setting | Value |\n")
+		sb.WriteString("|---------|-------|\n")
+	}
+	row := func(setting, val string, keys ...string) {
+		if scope := scopeOf(project, keys...); scope != "" {
+			val += fmt.Sprintf(" _(%s)_", scope)
+		}
+		if f.Provenance {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", setting, val, sourceOf(project, keys...)))
+		} else {
+			sb.WriteString(fmt.Sprintf("| %s | %s |\n", setting, val))
+		}
+	}
+	row("Type", project.Type, "type")
+	row("Path", fmt.Sprintf("`%s`", project.Path))
+	row("PHP", project.PHPVersion, "php_version")
+	row("Webserver", project.Webserver, "webserver_type")
+	row("Database", fmt.Sprintf("%s %s", project.Database.Type, project.Database.Version), "database.type", "database.version")
 	if project.NodeJS != "" {
-		sb.WriteString(fmt.Sprintf("| Node.js | %s |\n", project.NodeJS))
+		row("Node.js", project.NodeJS, "nodejs_version")
+	}
+	if project.Docroot != "" {
+		row("Docroot", fmt.Sprintf("`%s`", project.Docroot), "docroot")
+	}
+	if project.ComposerVersion != "" {
+		row("Composer", project.ComposerVersion, "composer_version")
+	}
+	if project.ComposerRoot != "" {
+		row("Composer root", fmt.Sprintf("`%s`", project.ComposerRoot), "composer_root")
+	}
+	row("Xdebug", enabledOrDisabled(project.XdebugEnabled), "xdebug_enabled")
+	if project.PerformanceMode != "" {
+		row("Performance mode", project.PerformanceMode, "performance_mode")
+	}
+	if project.Timezone != "" {
+		row("Timezone", project.Timezone, "timezone")
+	}
+	if len(project.UploadDirs) > 0 {
+		row("Upload dirs", strings.Join(project.UploadDirs, ", "), "upload_dirs")
+	}
+	if len(project.OmitContainers) > 0 {
+		row("Omitted containers", strings.Join(project.OmitContainers, ", "), "omit_containers")
+	}
+	if f.Verbose {
+		row("Router ports", fmt.Sprintf("http %s, https %s", project.RouterHTTPPort, project.RouterHTTPSPort), "router_http_port", "router_https_port")
+		if project.XdebugIDELocation != "" {
+			row("Xdebug IDE location", project.XdebugIDELocation, "xdebug_ide_location")
+		}
+		if project.XHProfMode != "" {
+			row("XHProf mode", project.XHProfMode, "xhprof_mode")
+		}
+		row("Corepack", enabledOrDisabled(project.CorepackEnable), "corepack_enable")
+		if len(project.WebimageExtraPackages) > 0 {
+			row("Web image packages", strings.Join(project.WebimageExtraPackages, ", "), "webimage_extra_packages")
+		}
+		if len(project.DBImageExtraPackages) > 0 {
+			row("DB image packages", strings.Join(project.DBImageExtraPackages, ", "), "dbimage_extra_packages")
+		}
+	}
+
+	if len(project.URLs) > 0 {
+		sb.WriteString("\n## URLs\n\n")
+		for _, u := range project.URLs {
+			// Wildcard patterns are not clickable, so show them as code
+			if strings.Contains(u, "*") {
+				sb.WriteString(fmt.Sprintf("- `%s`\n", u))
+			} else {
+				sb.WriteString(fmt.Sprintf("- <%s>\n", u))
+			}
+		}
+	}
+
+	if f.Verbose && (len(project.WebExtraExposedPorts) > 0 || len(project.WebExtraDaemons) > 0) {
+		sb.WriteString("\n## Web Container\n\n")
+		if len(project.WebExtraExposedPorts) > 0 {
+			sb.WriteString("**Exposed ports:**\n\n")
+			for _, p := range project.WebExtraExposedPorts {
+				sb.WriteString(fmt.Sprintf("- **%s**: %s\n", p.Name, exposedPortSummary(p)))
+			}
+			sb.WriteString("\n")
+		}
+		if len(project.WebExtraDaemons) > 0 {
+			sb.WriteString("**Daemons:**\n\n")
+			for _, d := range project.WebExtraDaemons {
+				sb.WriteString(fmt.Sprintf("- **%s**: `%s`\n", d.Name, d.Command))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(project.ImageCustomizations) > 0 {
+		sb.WriteString("\n## Image Customization\n\n")
+		for _, c := range project.ImageCustomizations {
+			sb.WriteString(fmt.Sprintf("**%s:**\n\n", imageFileLabel(c)))
+			for _, group := range packageGroups(c.Packages) {
+				sb.WriteString(fmt.Sprintf("- %s\n", group))
+			}
+			if f.Verbose {
+				for _, v := range c.Args {
+					sb.WriteString(fmt.Sprintf("- `ARG %s`\n", argDeclaration(v)))
+				}
+				for _, v := range c.Env {
+					sb.WriteString(fmt.Sprintf("- `ENV %s=%s`\n", v.Key, v.Value))
+				}
+				for _, cp := range c.Copies {
+					sb.WriteString(fmt.Sprintf("- `COPY %s %s`\n", strings.Join(cp.From, " "), cp.To))
+				}
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(project.Overrides) > 0 {
+		sb.WriteString("\n## Overrides\n\n")
+		for _, o := range project.Overrides {
+			sb.WriteString(fmt.Sprintf("**%s**\n\n", overrideLabel(o)))
+			if len(o.Settings) == 0 {
+				continue
+			}
+			for _, setting := range o.Settings {
+				sb.WriteString(fmt.Sprintf("- `%s`\n", settingLabel(setting)))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if f.Verbose && len(project.Environment) > 0 {
+		sb.WriteString("\n## Environment\n\n")
+		for _, service := range sortedKeys(project.Environment) {
+			sb.WriteString(fmt.Sprintf("**%s:**\n\n", service))
+			for _, v := range project.Environment[service] {
+				if f.Provenance {
+					sb.WriteString(fmt.Sprintf("- `%s=%s` (%s)\n", v.Key, v.Value, v.Source))
+				} else {
+					sb.WriteString(fmt.Sprintf("- `%s=%s`\n", v.Key, v.Value))
+				}
+			}
+			sb.WriteString("\n")
+		}
 	}
 
 	if len(project.DevPaths) > 0 {
@@ -38,25 +173,129 @@ func (f *MarkdownFormatter) Format(project *model.Project) (string, error) {
 			sb.WriteString(fmt.Sprintf("### %s\n\n", dp.Path))
 			sb.WriteString(fmt.Sprintf("- **Type:** %s\n", dp.Type))
 			sb.WriteString(fmt.Sprintf("- **Source:** %s\n", dp.Source))
+			if dp.MountTarget != "" {
+				sb.WriteString(fmt.Sprintf("- **Mounted at:** %s\n", mountSummary(dp)))
+			}
+			if dp.UpdateHint != "" {
+				sb.WriteString(fmt.Sprintf("- **Note:** %s\n", dp.UpdateHint))
+			}
 			if len(dp.Packages) > 0 {
-				sb.WriteString(fmt.Sprintf("- **Packages:** %s\n", strings.Join(dp.Packages, ", ")))
+				sb.WriteString("- **Packages:**\n")
+			}
+			for _, pkg := range dp.Packages {
+				sb.WriteString(fmt.Sprintf("  - `%s` (%s)", pkg.Name, packageSummary(pkg)))
+				if f.Verbose && pkg.Description != "" {
+					sb.WriteString(" - " + pkg.Description)
+				}
+				sb.WriteString("\n")
 			}
 			sb.WriteString("\n")
 		}
 	}
 
-	if len(project.Services) > 0 {
+	if providers, examples := visibleProviders(project.Providers, f.Verbose); len(project.Providers) > 0 {
+		sb.WriteString("## Providers\n\n")
+		for _, p := range providers {
+			targets, problems := providerTargets(p)
+			sb.WriteString(fmt.Sprintf("### %s (%s)%s\n\n", p.Name, providerStatus(p, problems), generatedTag(p.Generated)))
+			for _, target := range targets {
+				sb.WriteString(fmt.Sprintf("- `%s`\n", target))
+			}
+			if len(p.RequiredEnv) > 0 {
+				sb.WriteString(fmt.Sprintf("- **Needs:** %s\n", strings.Join(p.RequiredEnv, ", ")))
+			}
+			if !p.Generated {
+				for _, problem := range problems {
+					sb.WriteString(fmt.Sprintf("- **Missing:** %s\n", problem))
+				}
+			}
+			sb.WriteString("\n")
+		}
+		if len(examples) > 0 {
+			sb.WriteString(fmt.Sprintf("%d unmodified example providers: %s\n\n", len(examples), strings.Join(examples, ", ")))
+		}
+	}
+
+	if len(project.Addons) > 0 {
+		sb.WriteString("## Add-ons\n\n")
+		for _, addon := range project.Addons {
+			sb.WriteString(fmt.Sprintf("### %s\n\n", addon.Name))
+			if summary := addonSummary(addon); summary != "" {
+				sb.WriteString(fmt.Sprintf("- **Source:** %s\n", summary))
+			}
+			if len(addon.Services) > 0 {
+				sb.WriteString(fmt.Sprintf("- **Services:** %s\n", strings.Join(addon.Services, ", ")))
+			}
+			if len(addon.Commands) > 0 {
+				sb.WriteString(fmt.Sprintf("- **Commands:** %s\n", strings.Join(addon.Commands, ", ")))
+			}
+			if f.Verbose && len(addon.Files) > 0 {
+				sb.WriteString(fmt.Sprintf("- **Files:** %s\n", strings.Join(addon.Files, ", ")))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if services := customServices(project); len(services) > 0 {
 		sb.WriteString("## Services\n\n")
-		for _, svc := range project.Services {
-			sb.WriteString(fmt.Sprintf("- **%s** (%s)\n", svc.Name, svc.Type))
+		for _, svc := range services {
+			if details := serviceDetails(svc); details != "" {
+				sb.WriteString(fmt.Sprintf("- **%s** (%s)%s: %s\n", svc.Name, svc.Type, generatedTag(svc.Generated), details))
+			} else {
+				sb.WriteString(fmt.Sprintf("- **%s** (%s)%s\n", svc.Name, svc.Type, generatedTag(svc.Generated)))
+			}
 		}
 		sb.WriteString("\n")
 	}
 
-	if f.Verbose && len(project.Commands) > 0 {
+	if commands := customCommands(project); f.Verbose && len(commands) > 0 {
 		sb.WriteString("## Custom Commands\n\n")
-		for _, cmd := range project.Commands {
-			sb.WriteString(fmt.Sprintf("- `%s` - %s\n", cmd.Name, cmd.Description))
+		containers, byContainer := commandContainers(commands)
+		for _, container := range containers {
+			sb.WriteString(fmt.Sprintf("**%s:**\n\n", container))
+			for _, cmd := range byContainer[container] {
+				sb.WriteString(fmt.Sprintf("- `%s` - %s%s\n", cmd.Name, cmd.Description, commandTags(cmd)))
+				if cmd.Usage != "" {
+					sb.WriteString(fmt.Sprintf("  - Usage: `%s`\n", cmd.Usage))
+				}
+				for _, example := range strings.Split(cmd.Example, "\n") {
+					if example != "" {
+						sb.WriteString(fmt.Sprintf("  - Example: `%s`\n", example))
+					}
+				}
+				if len(cmd.Flags) > 0 {
+					sb.WriteString(fmt.Sprintf("  - Flags: %s\n", flagSummary(cmd.Flags)))
+				}
+				if cmd.FlagsError != "" {
+					sb.WriteString(fmt.Sprintf("  - **Warning:** %s\n", cmd.FlagsError))
+				}
+				if cmd.Hidden != "" {
+					sb.WriteString(fmt.Sprintf("  - **Hidden:** %s\n", cmd.Hidden))
+				}
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if f.Verbose && len(project.Hooks) > 0 {
+		sb.WriteString("## Hooks\n\n")
+		for _, event := range sortedKeys(project.Hooks) {
+			sb.WriteString(fmt.Sprintf("**%s:**\n\n", event))
+			for _, h := range project.Hooks[event] {
+				if f.Provenance {
+					sb.WriteString(fmt.Sprintf("- `%s` (%s)\n", hookSummary(h), h.Source))
+				} else {
+					sb.WriteString(fmt.Sprintf("- `%s`\n", hookSummary(h)))
+				}
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(project.Warnings) > 0 {
+		sb.WriteString("## Warnings\n\n")
+		for _, w := range project.Warnings {
+			sb.WriteString(fmt.Sprintf("- %s\n", w))
 		}
 		sb.WriteString("\n")
 	}
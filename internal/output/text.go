@@ -4,16 +4,17 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/fatih/color"
 	"github.com/dkd-dobberkau/ddev-explain/internal/model"
+	"github.com/fatih/color"
 )
 
 type TextFormatter struct {
-	Verbose bool
+	Verbose    bool
+	Provenance bool
 }
 
-func NewTextFormatter(verbose bool) *TextFormatter {
-	return &TextFormatter{Verbose: verbose}
+func NewTextFormatter(verbose, provenance bool) *TextFormatter {
+	return &TextFormatter{Verbose: verbose, Provenance: provenance}
 }
 
 func (f *TextFormatter) Format(project *model.Project) (string, error) {
@@ -23,30 +24,209 @@ func (f *TextFormatter) Format(project *model.Project) (string, error) {
 	title := color.New(color.FgCyan, color.Bold)
 	label := color.New(color.FgYellow)
 	value := color.New(color.FgWhite)
+	source := color.New(color.FgHiBlack)
+
+	// provenance appends the file:line that set a value when --provenance is on
+	provenance := func(keys ...string) string {
+		if f.Provenance {
+			if src := sourceOf(project, keys...); src != "" {
+				return source.Sprintf("  [%s]", src) + "\n"
+			}
+		}
+		return "\n"
+	}
+
+	// origin also marks values inherited from global config or DDEV defaults
+	origin := func(keys ...string) string {
+		if scope := scopeOf(project, keys...); scope != "" {
+			return source.Sprintf(" (%s)", scope) + provenance(keys...)
+		}
+		return provenance(keys...)
+	}
 
 	sb.WriteString(title.Sprintf("DDEV Project: %s\n", project.Name))
 	sb.WriteString(strings.Repeat("-", 50) + "\n\n")
 
 	// Basic info
 	sb.WriteString(label.Sprint("Type:       "))
-	sb.WriteString(value.Sprintf("%s\n", valueOrDash(project.Type)))
+	sb.WriteString(value.Sprint(valueOrDash(project.Type)) + origin("type"))
 
 	sb.WriteString(label.Sprint("Path:       "))
 	sb.WriteString(value.Sprintf("%s\n", valueOrDash(project.Path)))
 
+	// HTTP variants are only listed in verbose mode
+	var urls []string
+	for _, u := range project.URLs {
+		if f.Verbose || strings.HasPrefix(u, "https://") {
+			urls = append(urls, u)
+		}
+	}
+	for i, u := range urls {
+		if i == 0 {
+			sb.WriteString(label.Sprint("URLs:       "))
+			sb.WriteString(value.Sprint(u) + provenance("name", "project_tld", "additional_hostnames", "additional_fqdns", "router_https_port"))
+		} else {
+			sb.WriteString("            " + value.Sprintf("%s\n", u))
+		}
+	}
+
 	sb.WriteString(label.Sprint("PHP:        "))
-	sb.WriteString(value.Sprintf("%s\n", valueOrDash(project.PHPVersion)))
+	sb.WriteString(value.Sprint(valueOrDash(project.PHPVersion)) + origin("php_version"))
 
 	sb.WriteString(label.Sprint("Webserver:  "))
-	sb.WriteString(value.Sprintf("%s\n", valueOrDash(project.Webserver)))
+	sb.WriteString(value.Sprint(valueOrDash(project.Webserver)) + origin("webserver_type"))
 
 	sb.WriteString(label.Sprint("Database:   "))
 	dbStr := strings.TrimSpace(project.Database.Type + " " + project.Database.Version)
-	sb.WriteString(value.Sprintf("%s\n", valueOrDash(dbStr)))
+	sb.WriteString(value.Sprint(valueOrDash(dbStr)) + origin("database.type", "database.version"))
 
 	if project.NodeJS != "" {
 		sb.WriteString(label.Sprint("Node.js:    "))
-		sb.WriteString(value.Sprintf("%s\n", project.NodeJS))
+		sb.WriteString(value.Sprint(project.NodeJS) + origin("nodejs_version"))
+	}
+
+	if project.Docroot != "" {
+		sb.WriteString(label.Sprint("Docroot:    "))
+		sb.WriteString(value.Sprint(project.Docroot) + origin("docroot"))
+	}
+
+	if project.ComposerVersion != "" {
+		sb.WriteString(label.Sprint("Composer:   "))
+		composerStr := project.ComposerVersion
+		if project.ComposerRoot != "" {
+			composerStr += " (root: " + project.ComposerRoot + ")"
+		}
+		sb.WriteString(value.Sprint(composerStr) + origin("composer_version", "composer_root"))
+	}
+
+	sb.WriteString(label.Sprint("Xdebug:     "))
+	sb.WriteString(value.Sprint(enabledOrDisabled(project.XdebugEnabled)) + origin("xdebug_enabled"))
+
+	if project.PerformanceMode != "" {
+		sb.WriteString(label.Sprint("Perf mode:  "))
+		sb.WriteString(value.Sprint(project.PerformanceMode) + origin("performance_mode"))
+	}
+
+	if project.Timezone != "" {
+		sb.WriteString(label.Sprint("Timezone:   "))
+		sb.WriteString(value.Sprint(project.Timezone) + origin("timezone"))
+	}
+
+	if len(project.UploadDirs) > 0 {
+		sb.WriteString(label.Sprint("Uploads:    "))
+		sb.WriteString(value.Sprint(strings.Join(project.UploadDirs, ", ")) + origin("upload_dirs"))
+	}
+
+	if len(project.OmitContainers) > 0 {
+		sb.WriteString(label.Sprint("Omitted:    "))
+		sb.WriteString(value.Sprint(strings.Join(project.OmitContainers, ", ")) + origin("omit_containers"))
+	}
+
+	if f.Verbose {
+		sb.WriteString(label.Sprint("Router:     "))
+		sb.WriteString(value.Sprintf("http %s, https %s", project.RouterHTTPPort, project.RouterHTTPSPort) + origin("router_http_port", "router_https_port"))
+
+		if project.XdebugIDELocation != "" {
+			sb.WriteString(label.Sprint("Xdebug IDE: "))
+			sb.WriteString(value.Sprint(project.XdebugIDELocation) + origin("xdebug_ide_location"))
+		}
+
+		if project.XHProfMode != "" {
+			sb.WriteString(label.Sprint("XHProf:     "))
+			sb.WriteString(value.Sprint(project.XHProfMode) + origin("xhprof_mode"))
+		}
+
+		sb.WriteString(label.Sprint("Corepack:   "))
+		sb.WriteString(value.Sprint(enabledOrDisabled(project.CorepackEnable)) + origin("corepack_enable"))
+	}
+
+	// Web container details (verbose only)
+	if f.Verbose && (len(project.WebimageExtraPackages) > 0 || len(project.DBImageExtraPackages) > 0 ||
+		len(project.WebExtraExposedPorts) > 0 || len(project.WebExtraDaemons) > 0) {
+		sb.WriteString("\n")
+		sb.WriteString(title.Sprint("Web Container\n"))
+		sb.WriteString(strings.Repeat("-", 50) + "\n")
+
+		if len(project.WebimageExtraPackages) > 0 {
+			sb.WriteString("Web image packages: " + strings.Join(project.WebimageExtraPackages, ", ") + origin("webimage_extra_packages"))
+		}
+		if len(project.DBImageExtraPackages) > 0 {
+			sb.WriteString("DB image packages:  " + strings.Join(project.DBImageExtraPackages, ", ") + origin("dbimage_extra_packages"))
+		}
+		if len(project.WebExtraExposedPorts) > 0 {
+			sb.WriteString("Exposed ports:" + origin("web_extra_exposed_ports"))
+			for _, p := range project.WebExtraExposedPorts {
+				sb.WriteString(fmt.Sprintf("    - %s: %s\n", p.Name, exposedPortSummary(p)))
+			}
+		}
+		if len(project.WebExtraDaemons) > 0 {
+			sb.WriteString("Daemons:" + origin("web_extra_daemons"))
+			for _, d := range project.WebExtraDaemons {
+				sb.WriteString(fmt.Sprintf("    - %s: %s\n", d.Name, d.Command))
+			}
+		}
+	}
+
+	// Image customization
+	if len(project.ImageCustomizations) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(title.Sprint("Image Customization\n"))
+		sb.WriteString(strings.Repeat("-", 50) + "\n")
+
+		for _, c := range project.ImageCustomizations {
+			sb.WriteString(fmt.Sprintf("* %s\n", imageFileLabel(c)))
+			for _, group := range packageGroups(c.Packages) {
+				sb.WriteString("   " + group + "\n")
+			}
+			if !f.Verbose {
+				continue
+			}
+			for _, v := range c.Args {
+				sb.WriteString("   ARG " + argDeclaration(v) + "\n")
+			}
+			for _, v := range c.Env {
+				sb.WriteString(fmt.Sprintf("   ENV %s=%s\n", v.Key, v.Value))
+			}
+			for _, cp := range c.Copies {
+				sb.WriteString(fmt.Sprintf("   COPY %s -> %s\n", strings.Join(cp.From, ", "), cp.To))
+			}
+		}
+	}
+
+	// Config overrides
+	if len(project.Overrides) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(title.Sprint("Overrides\n"))
+		sb.WriteString(strings.Repeat("-", 50) + "\n")
+
+		for _, o := range project.Overrides {
+			sb.WriteString(fmt.Sprintf("* %s\n", overrideLabel(o)))
+			for _, setting := range o.Settings {
+				sb.WriteString("   " + settingLabel(setting))
+				if f.Provenance {
+					sb.WriteString(source.Sprintf("  [%s:%d]", o.File, setting.Line))
+				}
+				sb.WriteString("\n")
+			}
+		}
+	}
+
+	// Environment (verbose only)
+	if f.Verbose && len(project.Environment) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(title.Sprint("Environment\n"))
+		sb.WriteString(strings.Repeat("-", 50) + "\n")
+
+		for _, service := range sortedKeys(project.Environment) {
+			sb.WriteString(fmt.Sprintf("* %s:\n", service))
+			for _, v := range project.Environment[service] {
+				sb.WriteString(fmt.Sprintf("    %s=%s", v.Key, v.Value))
+				if f.Provenance {
+					sb.WriteString(source.Sprintf("  [%s]", v.Source))
+				}
+				sb.WriteString("\n")
+			}
+		}
 	}
 
 	// Development Paths
@@ -59,32 +239,127 @@ func (f *TextFormatter) Format(project *model.Project) (string, error) {
 			typeIcon := getTypeIcon(dp.Type)
 			sb.WriteString(fmt.Sprintf("%s %s\n", typeIcon, dp.Path))
 			sb.WriteString(fmt.Sprintf("   Type: %s | Source: %s\n", dp.Type, dp.Source))
+			if dp.MountTarget != "" {
+				sb.WriteString("   Mounted at: " + mountSummary(dp) + "\n")
+			}
+			if dp.UpdateHint != "" {
+				sb.WriteString(color.YellowString("   Note: %s\n", dp.UpdateHint))
+			}
 
 			if len(dp.Packages) > 0 {
-				sb.WriteString("   Packages: " + strings.Join(dp.Packages, ", ") + "\n")
+				sb.WriteString("   Packages:\n")
+			}
+			for _, pkg := range dp.Packages {
+				line := fmt.Sprintf("     - %s (%s)\n", pkg.Name, packageSummary(pkg))
+				if installedElsewhere(pkg) {
+					line = color.YellowString(line)
+				}
+				sb.WriteString(line)
+				if f.Verbose && pkg.Description != "" {
+					sb.WriteString("       " + pkg.Description + "\n")
+				}
+			}
+		}
+	}
+
+	// Hosting providers; unmodified examples are only listed in verbose mode
+	if providers, examples := visibleProviders(project.Providers, f.Verbose); len(project.Providers) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(title.Sprint("Providers\n"))
+		sb.WriteString(strings.Repeat("-", 50) + "\n")
+
+		for _, p := range providers {
+			targets, problems := providerTargets(p)
+			sb.WriteString(fmt.Sprintf("* %s (%s)%s\n", p.Name, providerStatus(p, problems), generatedTag(p.Generated)))
+			for _, target := range targets {
+				sb.WriteString("   " + target + "\n")
+			}
+			if len(p.RequiredEnv) > 0 {
+				sb.WriteString("   Needs: " + strings.Join(p.RequiredEnv, ", ") + "\n")
+			}
+			if !p.Generated {
+				for _, problem := range problems {
+					sb.WriteString(color.YellowString("   Missing: %s\n", problem))
+				}
+			}
+			if f.Verbose {
+				for _, v := range p.Variables {
+					sb.WriteString(fmt.Sprintf("   %s=%s\n", v.Key, v.Value))
+				}
+			}
+		}
+		if len(examples) > 0 {
+			sb.WriteString(fmt.Sprintf("%d unmodified example providers (-v to list): %s\n", len(examples), strings.Join(examples, ", ")))
+		}
+	}
+
+	// Add-ons
+	if len(project.Addons) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(title.Sprint("Add-ons\n"))
+		sb.WriteString(strings.Repeat("-", 50) + "\n")
+
+		for _, addon := range project.Addons {
+			sb.WriteString(fmt.Sprintf("* %s", addon.Name))
+			if summary := addonSummary(addon); summary != "" {
+				sb.WriteString(fmt.Sprintf(" (%s)", summary))
+			}
+			sb.WriteString("\n")
+			if len(addon.Services) > 0 {
+				sb.WriteString("   Services: " + strings.Join(addon.Services, ", ") + "\n")
+			}
+			if len(addon.Commands) > 0 {
+				sb.WriteString("   Commands: " + strings.Join(addon.Commands, ", ") + "\n")
+			}
+			if f.Verbose && len(addon.Files) > 0 {
+				sb.WriteString("   Files: " + strings.Join(addon.Files, ", ") + "\n")
 			}
 		}
 	}
 
 	// Services
-	if len(project.Services) > 0 {
+	if services := customServices(project); len(services) > 0 {
 		sb.WriteString("\n")
 		sb.WriteString(title.Sprint("Services\n"))
 		sb.WriteString(strings.Repeat("-", 50) + "\n")
 
-		for _, svc := range project.Services {
-			sb.WriteString(fmt.Sprintf("* %s (%s)\n", svc.Name, svc.Type))
+		for _, svc := range services {
+			sb.WriteString(fmt.Sprintf("* %s (%s)%s\n", svc.Name, svc.Type, generatedTag(svc.Generated)))
+			if details := serviceDetails(svc); details != "" {
+				sb.WriteString("   " + details + "\n")
+			}
 		}
 	}
 
 	// Commands (verbose only)
-	if f.Verbose && len(project.Commands) > 0 {
+	if commands := customCommands(project); f.Verbose && len(commands) > 0 {
 		sb.WriteString("\n")
 		sb.WriteString(title.Sprint("Custom Commands\n"))
 		sb.WriteString(strings.Repeat("-", 50) + "\n")
 
-		for _, cmd := range project.Commands {
-			sb.WriteString(fmt.Sprintf("* %s - %s\n", cmd.Name, cmd.Description))
+		containers, byContainer := commandContainers(commands)
+		for _, container := range containers {
+			sb.WriteString(fmt.Sprintf("%s:\n", container))
+			for _, cmd := range byContainer[container] {
+				sb.WriteString(fmt.Sprintf("  * %s - %s%s\n", cmd.Name, cmd.Description, commandTags(cmd)))
+				if cmd.Usage != "" {
+					sb.WriteString("     Usage: " + cmd.Usage + "\n")
+				}
+				for _, example := range strings.Split(cmd.Example, "\n") {
+					if example != "" {
+						sb.WriteString("     Example: " + example + "\n")
+					}
+				}
+				if len(cmd.Flags) > 0 {
+					sb.WriteString("     Flags: " + flagSummary(cmd.Flags) + "\n")
+				}
+				if cmd.FlagsError != "" {
+					sb.WriteString(color.YellowString("     Warning: %s\n", cmd.FlagsError))
+				}
+				if cmd.Hidden != "" {
+					sb.WriteString(color.YellowString("     Hidden: %s\n", cmd.Hidden))
+				}
+			}
 		}
 	}
 
@@ -94,14 +369,29 @@ func (f *TextFormatter) Format(project *model.Project) (string, error) {
 		sb.WriteString(title.Sprint("Hooks\n"))
 		sb.WriteString(strings.Repeat("-", 50) + "\n")
 
-		for name, cmds := range project.Hooks {
-			sb.WriteString(fmt.Sprintf("* %s:\n", name))
-			for _, cmd := range cmds {
-				sb.WriteString(fmt.Sprintf("    - %s\n", cmd))
+		for _, event := range sortedKeys(project.Hooks) {
+			sb.WriteString(fmt.Sprintf("* %s:\n", event))
+			for _, h := range project.Hooks[event] {
+				sb.WriteString(fmt.Sprintf("    - %s", hookSummary(h)))
+				if f.Provenance {
+					sb.WriteString(source.Sprintf("  [%s]", h.Source))
+				}
+				sb.WriteString("\n")
 			}
 		}
 	}
 
+	// Warnings
+	if len(project.Warnings) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(title.Sprint("Warnings\n"))
+		sb.WriteString(strings.Repeat("-", 50) + "\n")
+
+		for _, w := range project.Warnings {
+			sb.WriteString(color.YellowString("! %s\n", w))
+		}
+	}
+
 	return sb.String(), nil
 }
 
@@ -109,6 +399,10 @@ func getTypeIcon(t string) string {
 	switch t {
 	case "composer-path":
 		return "[pkg]"
+	case "composer-vcs":
+		return "[vcs]"
+	case "composer-artifact":
+		return "[zip]"
 	case "symlink":
 		return "[lnk]"
 	case "mount":